@@ -1,32 +1,284 @@
 package binfile
 
 import (
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"unicode/utf8"
 )
 
-// Processes the annotations aquired from the 'bin' tag.
-// Removes optional indentation spaces, splits them by comma and removes empty entries.
+// The kinds of annotations by the value they take.
+const (
+	annotationFlag          = iota // has no value, ex.: 'trim'
+	annotationValue                // has a value after a colon, ex.: 'default:<literal>'
+	annotationChar                 // has a single byte as value, ex.: 'fill:<char>'
+	annotationOptionalChar         // has an optional single byte as value, ex.: 'trim' or 'trim:<char>'
+	annotationOptionalValue        // has an optional value, ex.: 'placeholder' or 'placeholder:<literal>'
+)
+
+// The known annotations by their key. The address annotation has no key, it is recognized by its form.
+var annotationKeys = map[string]int{
+	"array":        annotationValue,
+	"trim":         annotationOptionalChar,
+	"ltrim":        annotationOptionalChar,
+	"rtrim":        annotationOptionalChar,
+	"padspace":     annotationFlag,
+	"forcesign":    annotationFlag,
+	"precision":    annotationValue,
+	"default":      annotationValue,
+	"blankdefault": annotationFlag,
+	"fallback":     annotationValue,
+	"if":           annotationValue,
+	"required":     annotationFlag,
+	"min":          annotationValue,
+	"max":          annotationValue,
+	"pattern":      annotationValue,
+	"oneof":        annotationValue,
+	"align":        annotationValue,
+	"fill":         annotationChar,
+	"truncate":     annotationFlag,
+	"notation":     annotationValue,
+	"expdigits":    annotationValue,
+	"nopoint":      annotationFlag,
+	"decimal":      annotationChar,
+	"thousands":    annotationChar,
+	"rounding":     annotationValue,
+	"overflow":     annotationValue,
+	"sign":         annotationValue,
+	"placeholder":  annotationOptionalValue,
+}
+
+// The allowed values of the annotations which only take some.
+var annotationChoices = map[string][]string{
+	"align":    {"left", "right", "center"},
+	"notation": {"fixed", "sci", "auto"},
+	"rounding": {"half-up", "half-even", "truncate"},
+	"overflow": {"error", "clamp", "fill"},
+	"sign":     {"leading", "trailing", "space", "none"},
+}
+
+// The address annotation: an optional absolute position and a length, both decimal or hexadecimal with '0x' or '0X'.
+var addressAnnotationExpr = regexp.MustCompile(`^(\d+|0[xX][0-9a-fA-F]+)?:(\d+|0[xX][0-9a-fA-F]+)$`)
+
+var structAnnotations sync.Map // the annotations of the fields by struct type, see getFieldAnnotations
+
+// The tag of a struct field and the result of parsing it, cached in structAnnotations.
+type fieldAnnotations struct {
+	tag         string
+	annotations []string
+	err         error
+}
+
+// Processes the annotations aquired from the 'bin' tag. The tag is a comma separated list of annotations:
 //
-// Returns a string array of the annotations and a bool with true if there are actual entries in it.
-func getAnnotationList(tag string) ([]string, bool) {
+//	annotation = address | key | key ":" value
+//	address    = [number] ":" number          (decimal or hexadecimal with '0x', ex.: '0x1A:4')
+//	value      = text | "'" quoted text "'"   (the quoted text can contain commas, colons, spaces and escapes like '\r')
+//
+// Spaces around annotations and unquoted values are ignored, empty entries are skipped.
+// Every annotation is returned as 'key' or 'key:value' with the value unquoted, the address as written.
+//
+// Returns a string array of the annotations, a bool with true if there are actual entries in it and
+// an ErrorInvalidAnnotation for malformed, unknown or duplicate annotations.
+func getAnnotationList(tag string) ([]string, bool, error) {
+	var annotations, err = parseAnnotationList(tag)
+	return annotations, len(annotations) > 0, err
+}
 
-	tag = strings.Replace(tag, " ", "", -1)
-	var annotations = strings.Split(tag, ",")
-	var annotationsFiltered []string
-	for i := range annotations {
-		if len(annotations[i]) > 0 {
-			annotationsFiltered = append(annotationsFiltered, annotations[i])
+// Returns the tag of field 'fieldNo' of 'structType' - from a registered layout if there is one, otherwise the 'bin' tag -
+// along with its annotations as returned by getAnnotationList. They are parsed once per struct type, so the cache
+// only grows with the types processed and not with the tags of runtime schemas.
+func getFieldAnnotations(structType reflect.Type, fieldNo int) (string, []string, bool, error) {
+
+	var cached, isCached = structAnnotations.Load(structType)
+	if !isCached {
+		var tags = make([]string, structType.NumField())
+		for i := range tags {
+			tags[i] = structType.Field(i).Tag.Get("bin")
 		}
+		cached, _ = structAnnotations.LoadOrStore(structType, parseFieldAnnotations(tags)) // a layout registered meanwhile is kept
 	}
 
-	return annotationsFiltered, len(annotationsFiltered) > 0
+	var field = cached.([]fieldAnnotations)[fieldNo]
+	return field.tag, field.annotations, len(field.annotations) > 0, field.err
+}
+
+// Parses the tags of all fields of a struct for structAnnotations.
+func parseFieldAnnotations(tags []string) []fieldAnnotations {
+	var fields = make([]fieldAnnotations, len(tags))
+	for i, tag := range tags {
+		var annotations, err = parseAnnotationList(tag)
+		fields[i] = fieldAnnotations{tag: tag, annotations: annotations, err: err}
+	}
+	return fields
 }
 
-// Checks the annotation array if the 'trim' annotation is in it and returns a bool accordingly.
-func hasAnnotationTrim(annotationList []string) bool {
-	return sliceContainsString(annotationList, "trim")
+// Parses a tag as described in getAnnotationList.
+func parseAnnotationList(tag string) ([]string, error) {
+
+	var annotations []string
+	var keys = map[string]bool{}
+
+	for position := 0; position < len(tag); {
+
+		var entry, key, value, hasValue, next, err = scanAnnotation(tag, position)
+		position = next
+		if err != nil {
+			return nil, err
+		}
+		if entry == "" {
+			continue
+		}
+
+		if key == "" || (key[0] >= '0' && key[0] <= '9') {
+			if !hasValue || !addressAnnotationExpr.MatchString(entry) {
+				return nil, newInvalidAnnotationError(entry, ErrorMalformedAnnotation)
+			}
+			if _, _, err = readAddressAnnotation(entry); err != nil {
+				return nil, newInvalidAnnotationError(entry, newInvalidAddressAnnotationError(err))
+			}
+			key = "" // only one address per field
+		} else if kind, isKnown := annotationKeys[key]; !isKnown {
+			return nil, newInvalidAnnotationError(entry, ErrorUnknownAnnotation)
+		} else if (kind == annotationFlag && hasValue) || ((kind == annotationValue || kind == annotationChar) && !hasValue) {
+			return nil, newInvalidAnnotationError(entry, ErrorMalformedAnnotation)
+		} else if hasValue {
+			if (kind == annotationChar || kind == annotationOptionalChar) && len(value) != 1 {
+				return nil, newInvalidAnnotationError(entry, ErrorInvalidAnnotationValue)
+			}
+			if choices, hasChoices := annotationChoices[key]; hasChoices && !sliceContainsString(choices, value) {
+				return nil, newInvalidAnnotationError(entry, ErrorInvalidAnnotationValue)
+			}
+			entry = key + ":" + value
+		}
+
+		if keys[key] {
+			return nil, newInvalidAnnotationError(entry, ErrorDuplicateAnnotation)
+		}
+		keys[key] = true
+
+		annotations = append(annotations, entry)
+	}
+
+	return annotations, nil
+}
+
+// Reads the annotation starting at 'position' of 'tag' up to the next comma outside of quotes.
+// Returns the annotation as written without the surrounding spaces, its key, its unquoted value, a bool which is true
+// if it has a value and the position after the comma. An empty annotation is returned for empty entries.
+func scanAnnotation(tag string, position int) (string, string, string, bool, int, error) {
+
+	var start = position
+	for position < len(tag) && tag[position] != ',' && tag[position] != ':' {
+		position++
+	}
+	var key = strings.TrimSpace(tag[start:position])
+
+	if position >= len(tag) || tag[position] == ',' {
+		return key, key, "", false, position + 1, nil
+	}
+
+	position++ // the colon
+	for position < len(tag) && tag[position] == ' ' {
+		position++
+	}
+
+	var value string
+	if position < len(tag) && tag[position] == '\'' {
+
+		var quoted strings.Builder
+		position++
+		for {
+			if position >= len(tag) {
+				return "", "", "", false, position, newInvalidAnnotationError(strings.TrimSpace(tag[start:]), ErrorMalformedAnnotation)
+			}
+			if tag[position] == '\'' {
+				position++
+				break
+			}
+			if char, size := utf8.DecodeRuneInString(tag[position:]); char == utf8.RuneError && size == 1 {
+				quoted.WriteByte(tag[position]) // not UTF-8, kept as is
+				position++
+				continue
+			}
+			var char, isMultibyte, rest, err = strconv.UnquoteChar(tag[position:], '\'')
+			if err != nil {
+				return "", "", "", false, position, newInvalidAnnotationError(strings.TrimSpace(tag[start:]), ErrorMalformedAnnotation)
+			}
+			if isMultibyte {
+				quoted.WriteRune(char)
+			} else {
+				quoted.WriteByte(byte(char)) // escapes like '\xFF' are single bytes
+			}
+			position = len(tag) - len(rest)
+		}
+		value = quoted.String()
+
+		for position < len(tag) && tag[position] == ' ' {
+			position++
+		}
+		if position < len(tag) && tag[position] != ',' {
+			return "", "", "", false, position, newInvalidAnnotationError(strings.TrimSpace(tag[start:]), ErrorMalformedAnnotation)
+		}
+
+	} else {
+
+		var valueStart = position
+		for position < len(tag) && tag[position] != ',' {
+			position++
+		}
+		value = strings.TrimSpace(tag[valueStart:position])
+	}
+
+	return strings.TrimSpace(tag[start:position]), key, value, true, position + 1, nil
+}
+
+// Returns the 'value' for an annotation, quoted if it contains commas, quotes, backslashes, control characters
+// or surrounding spaces. (see getAnnotationList)
+func quoteAnnotationValue(value string) string {
+
+	var needsQuotes = value != strings.TrimSpace(value) || strings.HasPrefix(value, "'")
+	for i := 0; i < len(value) && !needsQuotes; i++ {
+		needsQuotes = value[i] == ',' || value[i] == '\\' || value[i] < 0x20 || value[i] == 0x7f
+	}
+	if !needsQuotes {
+		return value
+	}
+
+	var quoted strings.Builder
+	quoted.WriteByte('\'')
+	for i := 0; i < len(value); i++ {
+		switch char := value[i]; {
+		case char == '\'' || char == '\\':
+			quoted.WriteByte('\\')
+			quoted.WriteByte(char)
+		case char < 0x20 || char == 0x7f:
+			quoted.WriteString(`\x` + strconv.FormatUint(uint64(char)|0x100, 16)[1:])
+		default:
+			quoted.WriteByte(char)
+		}
+	}
+	quoted.WriteByte('\'')
+
+	return quoted.String()
+}
+
+// Returns the character of the trim annotation 'key' - 'trim', 'ltrim' or 'rtrim' - along with a bool which is true if found.
+// Without a character, 'defaultChar' is returned. (', ok' idiom)
+func getTrimAnnotation(annotationList []string, key string, defaultChar byte) (byte, bool) {
+
+	for _, val := range annotationList {
+		if val == key {
+			return defaultChar, true
+		}
+		if strings.HasPrefix(val, key+":") {
+			return val[len(key)+1], true
+		}
+	}
+
+	return defaultChar, false
 }
 
 // Checks the annotation array if the 'padspace' annotation is in it and returns a bool accordingly.
@@ -39,11 +291,59 @@ func hasAnnotationForceSign(annotationList []string) bool {
 	return sliceContainsString(annotationList, "forcesign")
 }
 
+// Checks the annotation array if the 'blankdefault' annotation is in it and returns a bool accordingly.
+func hasAnnotationBlankDefault(annotationList []string) bool {
+	return sliceContainsString(annotationList, "blankdefault")
+}
+
+// Checks the annotation array if the 'truncate' annotation is in it and returns a bool accordingly.
+func hasAnnotationTruncate(annotationList []string) bool {
+	return sliceContainsString(annotationList, "truncate")
+}
+
+// Returns the alignment from the 'align' annotation along with a bool which is true if found. (', ok' idiom)
+// The default is "right", as strings were always right-aligned.
+func getAlignAnnotation(annotationList []string) (string, bool) {
+
+	for _, val := range annotationList {
+		if strings.HasPrefix(val, "align:") {
+			return strings.TrimPrefix(val, "align:"), true
+		}
+	}
+
+	return "right", false
+}
+
+// Returns the character from the 'fill' annotation along with a bool which is true if found. (', ok' idiom)
+// The default is a space.
+func getFillAnnotation(annotationList []string) (byte, bool) {
+
+	for _, val := range annotationList {
+		if strings.HasPrefix(val, "fill:") {
+			return val[len("fill:")], true
+		}
+	}
+
+	return ' ', false
+}
+
+// Returns the literal from the 'default' annotation along with a bool which is true if found. (', ok' idiom)
+func getDefaultAnnotation(annotationList []string) (string, bool) {
+
+	for _, val := range annotationList {
+		if strings.HasPrefix(val, "default:") {
+			return strings.TrimPrefix(val, "default:"), true
+		}
+	}
+
+	return "", false
+}
+
 // Finds and returns the 'array' annotation in the annotation list along with a bool which value is true if found.
 func getArrayAnnotation(annotationList []string) (string, bool) {
 
-	for i, val := range annotationList {
-		if strings.HasPrefix(annotationList[i], "array") {
+	for _, val := range annotationList {
+		if strings.HasPrefix(val, "array:") {
 			return val, true
 		}
 	}
@@ -51,18 +351,43 @@ func getArrayAnnotation(annotationList []string) (string, bool) {
 	return "", false
 }
 
+// Returns the field name from the 'fallback' annotation along with a bool which is true if found. (', ok' idiom)
+func getFallbackAnnotation(annotationList []string) (string, bool) {
+
+	for _, val := range annotationList {
+		if strings.HasPrefix(val, "fallback:") {
+			return strings.TrimPrefix(val, "fallback:"), true
+		}
+	}
+
+	return "", false
+}
+
+// Returns the referenced field name and the expected value from the 'if' annotation along with a bool which is true if found.
+// The annotation has the form 'if:<field_name>=<value>'. An error is given back if it is malformed.
+//
+// NOTE: You should only consider the values valid if the returned bool is true. (', ok' idiom)
+func getConditionAnnotation(annotationList []string) (string, string, bool, error) {
+
+	for _, val := range annotationList {
+		if strings.HasPrefix(val, "if:") {
+			var condition = strings.SplitN(strings.TrimPrefix(val, "if:"), "=", 2)
+			if len(condition) != 2 || condition[0] == "" {
+				return "", "", false, newInvalidConditionError(val, ErrorMalformedCondition)
+			}
+			return condition[0], condition[1], true, nil
+		}
+	}
+
+	return "", "", false, nil
+}
+
 // Checks the provided 'array' annotation if it's a terminated type and returns a bool accordingly.
 //
 // NOTE: Will also return false on mistyped values.
 func isArrayTypeTerminator(arrayAnnotation string) bool {
 
-	var vals = strings.Split(arrayAnnotation, ":")
-
-	if len(vals) == 2 && vals[1] == "terminator" {
-		return true
-	}
-
-	return false
+	return strings.TrimPrefix(arrayAnnotation, "array:") == "terminator"
 }
 
 // Checks the provided 'array' annotation it it has a valid integer value and returns it along with a true boolean value.
@@ -70,13 +395,7 @@ func isArrayTypeTerminator(arrayAnnotation string) bool {
 // Note: Will return a false on missing or non-integer values in which case the value should not be used. (', ok' idiom)
 func getArrayFixedSize(arrayAnnotation string) (int, bool) {
 
-	var vals = strings.Split(arrayAnnotation, ":")
-
-	if len(vals) != 2 {
-		return 0, false
-	}
-
-	if num, err := strconv.Atoi(vals[1]); err == nil && num > 0 {
+	if num, err := strconv.Atoi(strings.TrimPrefix(arrayAnnotation, "array:")); err == nil && num > 0 {
 		return num, true
 	}
 
@@ -89,13 +408,11 @@ func getArrayFixedSize(arrayAnnotation string) (int, bool) {
 // to use a field named 'terminator' for an array size, you should refrain from it and check for a terminated type first.
 func getArraySizeFieldName(arrayAnnotation string) (string, bool) {
 
-	var vals = strings.Split(arrayAnnotation, ":")
-
-	if len(vals) != 2 {
+	if !strings.HasPrefix(arrayAnnotation, "array:") {
 		return "", false
 	}
 
-	return vals[1], true
+	return strings.TrimPrefix(arrayAnnotation, "array:"), true
 }
 
 // Returns the absolute position and the relative length from the annotation list along with a bool which is true if found.
@@ -120,20 +437,13 @@ func getAddressAnnotation(annotationList []string) (int, int, bool, error) {
 
 // isValidAddressAnnotation - verify the valdity of an address annotation
 // returns
-//   - true when the string matches /^\d*:\d+$/
+//   - true when the string matches /^(\d+|0[xX][0-9a-fA-F]+)?:(\d+|0[xX][0-9a-fA-F]+)$/
 //   - false otherwise
 func isValidAddressAnnotation(str string) bool {
-
-	expr, err := regexp.Compile(`^\d*:\d+$`)
-	if err != nil {
-		panic("Invalid Expression in regexp (this error should never occur)")
-	}
-	matched := expr.Match([]byte(str))
-
-	return matched
+	return addressAnnotationExpr.MatchString(str)
 }
 
-// Read an address annotation with format "absolute:length" or ":length".
+// Read an address annotation with format "absolute:length" or ":length", decimal or hexadecimal with '0x'.
 // Gives an error if the values aren't valid integers.
 func readAddressAnnotation(str string) (int, int, error) {
 	var abs = -1
@@ -144,7 +454,7 @@ func readAddressAnnotation(str string) (int, int, error) {
 
 	if len(parts) >= 1 { // the 1st part is the absolute address
 		if parts[0] != "" {
-			if abs, err = strconv.Atoi(parts[0]); err != nil {
+			if abs, err = parseAnnotationInt(parts[0]); err != nil {
 				return -1, -1, newInvalidAbsolutePositionError(err)
 			}
 		}
@@ -152,7 +462,7 @@ func readAddressAnnotation(str string) (int, int, error) {
 
 	if len(parts) >= 2 { // the 2nd part is the length of the field
 		if parts[1] != "" {
-			if length, err = strconv.Atoi(parts[1]); err != nil {
+			if length, err = parseAnnotationInt(parts[1]); err != nil {
 				return -1, -1, newInvalidRelativeLengthError(err)
 			}
 		}
@@ -161,27 +471,163 @@ func readAddressAnnotation(str string) (int, int, error) {
 	return abs, length, err
 }
 
+// Parses a decimal integer or a hexadecimal one with '0x'. Leading zeros of decimals don't make them octal.
+func parseAnnotationInt(str string) (int, error) {
+	if strings.HasPrefix(str, "0x") || strings.HasPrefix(str, "0X") {
+		var value, err = strconv.ParseInt(str[2:], 16, strconv.IntSize)
+		return int(value), err
+	}
+	return strconv.Atoi(str)
+}
+
 // Finds and returns the precision from the annotation list. The default value is '-1' which is a valid value for strconv.FormatFloat.
 // Gives an error if the value is not a valid integer that's bigger than -1.
 func getPrecisionFromAnnotation(annotationList []string) (int, error) {
 
-	for i, precisionAnnotation := range annotationList {
-		if strings.HasPrefix(annotationList[i], "precision") {
-
-			var vals = strings.Split(precisionAnnotation, ":")
+	for _, precisionAnnotation := range annotationList {
+		if strings.HasPrefix(precisionAnnotation, "precision:") {
 
-			if len(vals) != 2 {
-				return -1, nil
-			}
+			var value = strings.TrimPrefix(precisionAnnotation, "precision:")
 
 			// -1 is a valid precision - means all digits needed
-			if precision, err := strconv.Atoi(vals[1]); err == nil && precision >= -1 {
+			if precision, err := strconv.Atoi(value); err == nil && precision >= -1 {
 				return precision, nil
 			}
 
-			return -1, newInvalidPrecisionError(vals[1])
+			return -1, newInvalidPrecisionError(value)
 		}
 	}
 
 	return -1, nil
 }
+
+// Returns the notation from the 'notation' annotation along with a bool which is true if found. (', ok' idiom)
+// The default is "fixed".
+func getNotationAnnotation(annotationList []string) (string, bool) {
+
+	for _, val := range annotationList {
+		if strings.HasPrefix(val, "notation:") {
+			return strings.TrimPrefix(val, "notation:"), true
+		}
+	}
+
+	return "fixed", false
+}
+
+// Returns the minimum number of exponent digits from the 'expdigits' annotation. The default is 2, like strconv.FormatFloat writes.
+// Gives an error if the value is not an integer between 1 and 3.
+func getExpDigitsAnnotation(annotationList []string) (int, error) {
+
+	for _, val := range annotationList {
+		if strings.HasPrefix(val, "expdigits:") {
+			if digits, err := strconv.Atoi(strings.TrimPrefix(val, "expdigits:")); err == nil && digits >= 1 && digits <= 3 {
+				return digits, nil
+			}
+			return 2, newInvalidAnnotationError(val, ErrorInvalidAnnotationValue)
+		}
+	}
+
+	return 2, nil
+}
+
+// Checks the annotation array if the 'nopoint' annotation is in it and returns a bool accordingly.
+func hasAnnotationNoPoint(annotationList []string) bool {
+	return sliceContainsString(annotationList, "nopoint")
+}
+
+// Returns the number of implied decimal places along with a bool which is true if there are any. (', ok' idiom)
+// 'nopoint' with a 'precision' above 0 implies the decimals: the digits are written without a point, ex.: 6.40 as "640" for 'nopoint,precision:2'.
+func getImpliedDecimals(annotationList []string) (int, bool) {
+
+	if !hasAnnotationNoPoint(annotationList) {
+		return 0, false
+	}
+	if precision, err := getPrecisionFromAnnotation(annotationList); err == nil && precision > 0 {
+		return precision, true
+	}
+
+	return 0, false
+}
+
+// Returns the decimal separator of floats from the 'decimal' annotation, otherwise the one of the options. The default is '.'.
+func getDecimalSeparator(annotationList []string, opts *options) byte {
+
+	for _, val := range annotationList {
+		if strings.HasPrefix(val, "decimal:") {
+			return val[len("decimal:")]
+		}
+	}
+
+	if opts.decimalSeparator != 0 {
+		return opts.decimalSeparator
+	}
+	return '.'
+}
+
+// Returns the thousands separator allowed in floats read from the input along with a bool which is true if there is one.
+// It is taken from the 'thousands' annotation, otherwise from the options. (', ok' idiom)
+func getThousandsSeparator(annotationList []string, opts *options) (byte, bool) {
+
+	for _, val := range annotationList {
+		if strings.HasPrefix(val, "thousands:") {
+			return val[len("thousands:")], true
+		}
+	}
+
+	return opts.thousandsSeparator, opts.thousandsSeparator != 0
+}
+
+// Returns the mode from the 'rounding' annotation along with a bool which is true if found. (', ok' idiom)
+// Without it, an empty string is returned and floats are rounded by strconv.FormatFloat.
+func getRoundingAnnotation(annotationList []string) (string, bool) {
+
+	for _, val := range annotationList {
+		if strings.HasPrefix(val, "rounding:") {
+			return strings.TrimPrefix(val, "rounding:"), true
+		}
+	}
+
+	return "", false
+}
+
+// Returns the policy from the 'overflow' annotation along with a bool which is true if found. (', ok' idiom)
+// The default is "error".
+func getOverflowAnnotation(annotationList []string) (string, bool) {
+
+	for _, val := range annotationList {
+		if strings.HasPrefix(val, "overflow:") {
+			return strings.TrimPrefix(val, "overflow:"), true
+		}
+	}
+
+	return "error", false
+}
+
+// Returns the placement from the 'sign' annotation along with a bool which is true if found. (', ok' idiom)
+// The default is "leading".
+func getSignAnnotation(annotationList []string) (string, bool) {
+
+	for _, val := range annotationList {
+		if strings.HasPrefix(val, "sign:") {
+			return strings.TrimPrefix(val, "sign:"), true
+		}
+	}
+
+	return "leading", false
+}
+
+// Returns the literals from the 'placeholder' annotation along with a bool which is true if found. (', ok' idiom)
+// The annotation has the form 'placeholder' or 'placeholder:<literal>|<literal>'.
+func getPlaceholderAnnotation(annotationList []string) ([]string, bool) {
+
+	for _, val := range annotationList {
+		if val == "placeholder" {
+			return []string{}, true
+		}
+		if strings.HasPrefix(val, "placeholder:") {
+			return strings.Split(strings.TrimPrefix(val, "placeholder:"), "|"), true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,379 @@
+// Command binfile decodes, encodes, dumps and validates binary transmissions described by a layout file.
+//
+// Usage:
+//
+//	binfile decode   -layout <file> [-format json|ndjson|csv] [-lenient] [-strict] [flags] [input]
+//	binfile encode   -layout <file> [flags] [input]
+//	binfile dump     -layout <file> [flags] [input]
+//	binfile validate -layout <file> [-strict] [flags] [input]
+//
+// The layout is a JSON or YAML file describing the fields of a record with the annotations of the 'bin' tag,
+// as read by binfile.ParseSchema.
+// The input is read from the file given or from stdin, the output is written to stdout or the file given with -o.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // the timezones are checked independent of the system
+
+	"github.com/DRK-Blutspende-BaWueHe/go-binfile"
+)
+
+const usage = `usage: binfile <command> -layout <file> [flags] [input]
+
+commands:
+  decode    decodes the input into JSON, NDJSON or CSV
+  encode    encodes records read as JSON or NDJSON
+  dump      prints the input as hex and ASCII labeled with the fields
+  validate  checks the input against the layout and its validation annotations
+
+run 'binfile <command> -h' for the flags of a command
+`
+
+// The names of the -encoding flag.
+var encodings = map[string]binfile.Encoding{
+	"utf8":        binfile.EncodingUTF8,
+	"ascii":       binfile.EncodingASCII,
+	"windows1250": binfile.EncodingWindows1250,
+	"windows1251": binfile.EncodingWindows1251,
+	"windows1252": binfile.EncodingWindows1252,
+	"dos852":      binfile.EncodingDOS852,
+	"dos855":      binfile.EncodingDOS855,
+	"dos866":      binfile.EncodingDOS866,
+}
+
+// The settings of a single command run, parsed from the flags.
+type command struct {
+	name       string
+	layout     *binfile.Schema
+	recordType reflect.Type
+	terminator string
+	padding    byte
+	encoding   binfile.Encoding
+	timezone   binfile.Timezone
+	format     string
+	lenient    bool
+	strict     bool
+	options    []binfile.Option // the separators, shared by all commands
+	input      []byte
+	out        io.Writer
+	stderr     io.Writer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// Executes the command in 'args' and returns the exit code.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+
+	if len(args) == 0 || args[0] == "-h" || args[0] == "help" {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	var cmd, err = parseCommand(args[0], args[1:], stdin, stdout, stderr)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(stderr, "binfile %s: %s\n", args[0], err.Error())
+		}
+		return 2
+	}
+	if closer, isCloser := cmd.out.(io.Closer); isCloser {
+		defer closer.Close()
+	}
+
+	switch cmd.name {
+	case "decode":
+		return cmd.decode()
+	case "encode":
+		return cmd.encode()
+	case "dump":
+		return cmd.dump()
+	case "validate":
+		return cmd.validate()
+	}
+
+	return 2 // unreachable, the name is checked while parsing
+}
+
+// Parses the flags of the command 'name' and reads the layout and the input.
+func parseCommand(name string, args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) (*command, error) {
+
+	switch name {
+	case "decode", "encode", "dump", "validate":
+	default:
+		return nil, fmt.Errorf("unknown command - use decode, encode, dump or validate")
+	}
+
+	var flags = flag.NewFlagSet("binfile "+name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var layoutPath = flags.String("layout", "", "layout file (JSON or YAML), required")
+	var outputPath = flags.String("o", "", "output file instead of stdout")
+	var terminator = flags.String("terminator", `\r`, "terminator of arrays and records, escape sequences like \\r\\n are supported")
+	var padding = flags.String("padding", " ", "padding byte for absolute positions when encoding, escape sequences like \\x00 are supported")
+	var encodingName = flags.String("encoding", "utf8", "encoding: utf8, ascii, windows1250, windows1251, windows1252, dos852, dos855 or dos866")
+	var timezone = flags.String("timezone", string(binfile.TimezoneUTC), "timezone, ex.: Europe/Berlin")
+	var decimal = flags.String("decimal", ".", "decimal separator of floats")
+	var thousands = flags.String("thousands", "", "thousands separator allowed in floats of the input")
+	var format, lenient, strict = new(string), new(bool), new(bool)
+	if name == "decode" {
+		format = flags.String("format", formatJSON, "output format: json, ndjson or csv")
+		lenient = flags.Bool("lenient", false, "decode everything possible and report all problems")
+	}
+	if name == "decode" || name == "validate" {
+		strict = flags.Bool("strict", false, "reject bytes after the end of a record")
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	var cmd = &command{name: name, format: *format, lenient: *lenient, strict: *strict, timezone: binfile.Timezone(*timezone), out: stdout, stderr: stderr}
+	var err error
+
+	// all flags are checked before the layout and the input are read
+	if *layoutPath == "" {
+		return nil, fmt.Errorf("missing -layout")
+	}
+	if name == "decode" && !isKnownFormat(cmd.format) {
+		return nil, fmt.Errorf("unknown -format '%s' - use %s, %s or %s", cmd.format, formatJSON, formatNDJSON, formatCSV)
+	}
+	if cmd.terminator, err = unescape(*terminator); err != nil {
+		return nil, fmt.Errorf("invalid -terminator: %w", err)
+	}
+	var paddingText string
+	if paddingText, err = unescape(*padding); err != nil || len(paddingText) != 1 {
+		return nil, fmt.Errorf("invalid -padding '%s': has to be a single byte", *padding)
+	}
+	cmd.padding = paddingText[0]
+
+	if len(*decimal) != 1 {
+		return nil, fmt.Errorf("invalid -decimal '%s': has to be a single byte", *decimal)
+	}
+	cmd.options = append(cmd.options, binfile.DecimalSeparator((*decimal)[0]))
+	if len(*thousands) > 1 {
+		return nil, fmt.Errorf("invalid -thousands '%s': has to be a single byte", *thousands)
+	} else if len(*thousands) == 1 {
+		cmd.options = append(cmd.options, binfile.ThousandsSeparator((*thousands)[0]))
+	}
+
+	var isKnownEncoding bool
+	if cmd.encoding, isKnownEncoding = encodings[strings.ToLower(*encodingName)]; !isKnownEncoding {
+		return nil, fmt.Errorf("unknown -encoding '%s'", *encodingName)
+	}
+	if _, err = time.LoadLocation(*timezone); err != nil || *timezone == "" {
+		return nil, fmt.Errorf("unknown -timezone '%s' - use a name of the IANA time zone database, ex.: Europe/Berlin", *timezone)
+	}
+
+	if flags.NArg() > 1 {
+		return nil, fmt.Errorf("too many arguments - only one input file is supported")
+	}
+
+	if cmd.layout, err = loadLayout(*layoutPath); err != nil {
+		return nil, err
+	}
+	if cmd.recordType, err = cmd.layout.RecordType(); err != nil {
+		return nil, err
+	}
+
+	if flags.NArg() == 1 && flags.Arg(0) != "-" {
+		cmd.input, err = os.ReadFile(flags.Arg(0))
+	} else {
+		cmd.input, err = io.ReadAll(stdin)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if *outputPath != "" {
+		if cmd.out, err = os.Create(*outputPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return cmd, nil
+}
+
+// Reads a layout from a JSON or YAML file.
+func loadLayout(path string) (*binfile.Schema, error) {
+
+	var content, err = os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := binfile.ParseSchema(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid layout file '%s': %w", path, err)
+	}
+
+	return schema, nil
+}
+
+// Returns the text with its escape sequences replaced, ex.: `\r\n` to "\r\n".
+func unescape(text string) (string, error) {
+	return strconv.Unquote(`"` + strings.ReplaceAll(text, `"`, `\"`) + `"`)
+}
+
+// Returns a pointer to a new target for Unmarshal: a record or a slice of them.
+func (cmd *command) newTarget() reflect.Value {
+	if cmd.layout.Records {
+		return reflect.New(reflect.SliceOf(cmd.recordType))
+	}
+	return reflect.New(cmd.recordType)
+}
+
+// Returns the records in a target created by newTarget.
+func (cmd *command) recordsOf(target reflect.Value) []reflect.Value {
+	if !cmd.layout.Records {
+		return []reflect.Value{target.Elem()}
+	}
+	var records = make([]reflect.Value, target.Elem().Len())
+	for i := range records {
+		records[i] = target.Elem().Index(i)
+	}
+	return records
+}
+
+func (cmd *command) decode() int {
+
+	var options = cmd.options
+	if cmd.lenient {
+		options = append(options, binfile.Lenient())
+	}
+	if cmd.strict {
+		options = append(options, binfile.Strict())
+	}
+
+	var target = cmd.newTarget()
+	var _, err = binfile.Unmarshal(cmd.input, target.Interface(), cmd.encoding, cmd.timezone, cmd.terminator, options...)
+	if err != nil && !cmd.lenient {
+		cmd.printErrors(err)
+		return 1
+	}
+
+	if writeErr := writeRecords(cmd.out, cmd.format, cmd.recordsOf(target), cmd.layout.Records); writeErr != nil {
+		fmt.Fprintf(cmd.stderr, "binfile decode: %s\n", writeErr.Error())
+		return 1
+	}
+
+	if err != nil {
+		cmd.printErrors(err)
+		return 1
+	}
+	return 0
+}
+
+func (cmd *command) encode() int {
+
+	var records, err = cmd.readJSONRecords()
+	if err != nil {
+		fmt.Fprintf(cmd.stderr, "binfile encode: %s\n", err.Error())
+		return 1
+	}
+
+	var target interface{}
+	if cmd.layout.Records {
+		var slice = reflect.MakeSlice(reflect.SliceOf(cmd.recordType), 0, len(records))
+		target = reflect.Append(slice, records...).Interface()
+	} else if len(records) == 1 {
+		target = records[0].Interface()
+	} else {
+		fmt.Fprintf(cmd.stderr, "binfile encode: found %d records, but the layout is not for records\n", len(records))
+		return 1
+	}
+
+	output, err := binfile.Marshal(target, cmd.padding, cmd.encoding, cmd.timezone, cmd.terminator, cmd.options...)
+	if err != nil {
+		cmd.printErrors(err)
+		return 1
+	}
+
+	if _, err = cmd.out.Write(output); err != nil {
+		fmt.Fprintf(cmd.stderr, "binfile encode: %s\n", err.Error())
+		return 1
+	}
+	return 0
+}
+
+// Reads the records from the input: JSON objects, arrays of them or both one after another (NDJSON).
+func (cmd *command) readJSONRecords() ([]reflect.Value, error) {
+
+	var decoder = json.NewDecoder(strings.NewReader(string(cmd.input)))
+	decoder.DisallowUnknownFields()
+
+	var records []reflect.Value
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		var values = []json.RawMessage{raw}
+		if strings.HasPrefix(strings.TrimSpace(string(raw)), "[") {
+			if err := json.Unmarshal(raw, &values); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, value := range values {
+			var record = reflect.New(cmd.recordType)
+			var valueDecoder = json.NewDecoder(strings.NewReader(string(value)))
+			valueDecoder.DisallowUnknownFields()
+			if err := valueDecoder.Decode(record.Interface()); err != nil {
+				return nil, fmt.Errorf("record %d: %w", len(records), err)
+			}
+			records = append(records, record.Elem())
+		}
+	}
+
+	return records, nil
+}
+
+func (cmd *command) dump() int {
+	fmt.Fprint(cmd.out, binfile.Dump(cmd.input, cmd.newTarget().Interface(), cmd.encoding, cmd.timezone, cmd.terminator, cmd.options...))
+	return 0
+}
+
+func (cmd *command) validate() int {
+
+	var options = append([]binfile.Option{binfile.Lenient(), binfile.CollectValidationErrors()}, cmd.options...)
+	if cmd.strict {
+		options = append(options, binfile.Strict())
+	}
+
+	var target = cmd.newTarget()
+	var _, err = binfile.Unmarshal(cmd.input, target.Interface(), cmd.encoding, cmd.timezone, cmd.terminator, options...)
+	if err != nil {
+		cmd.printErrors(err)
+		return 1
+	}
+
+	fmt.Fprintf(cmd.out, "valid: %d record(s)\n", len(cmd.recordsOf(target)))
+	return 0
+}
+
+// Prints each of the errors in 'err' on its own line.
+func (cmd *command) printErrors(err error) {
+	var errCollection *binfile.ErrorCollection
+	if errors.As(err, &errCollection) {
+		for _, collectedErr := range errCollection.Errors {
+			fmt.Fprintln(cmd.stderr, collectedErr.Error())
+		}
+		return
+	}
+	fmt.Fprintln(cmd.stderr, err.Error())
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testLayoutYAML = `
+records: true
+fields:
+  - name: RecordType
+    type: string
+    bin: ":1"
+  - name: SampleId
+    type: string
+    bin: ":4,trim"
+  - name: Results
+    type: "[]struct"
+    bin: "array:terminator"
+    fields:
+      - name: TestCode
+        type: string
+        bin: ":2"
+      - name: Value
+        type: int
+        bin: ":3,max:500"
+`
+
+// Writes the layout into a temporary file and returns its path.
+func writeTestLayout(t *testing.T, name string, content string) string {
+	var path = filepath.Join(t.TempDir(), name)
+	assert.Nil(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// Runs the command line tool with the 'input' on stdin and returns the exit code, stdout and stderr.
+func runTest(input string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	var code = run(args, strings.NewReader(input), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestDecode(t *testing.T) {
+
+	var layoutPath = writeTestLayout(t, "layout.yaml", testLayoutYAML)
+	var input = "DS1  61001\r\rDS2  62002\r\r"
+
+	code, stdout, stderr := runTest(input, "decode", "-layout", layoutPath, "-format", "ndjson")
+	assert.Equal(t, 0, code, stderr)
+	assert.Equal(t, `{"RecordType":"D","SampleId":"S1","Results":[{"TestCode":"61","Value":1}]}`+"\n"+
+		`{"RecordType":"D","SampleId":"S2","Results":[{"TestCode":"62","Value":2}]}`+"\n", stdout)
+
+	code, stdout, _ = runTest(input, "decode", "-layout", layoutPath, "-format", "csv")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "RecordType,SampleId,Results\n"+
+		`D,S1,"[{""TestCode"":""61"",""Value"":1}]"`+"\n"+
+		`D,S2,"[{""TestCode"":""62"",""Value"":2}]"`+"\n", stdout)
+
+	code, _, stderr = runTest("DS1  61X01\r\r", "decode", "-layout", layoutPath)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "Results[0].Value")
+
+	//-------------------------------------------------------------------------
+
+	var jsonLayoutPath = writeTestLayout(t, "layout.json", `{"fields": [{"name": "Code", "type": "int", "bin": ":2"}]}`)
+
+	code, stdout, _ = runTest("42", "decode", "-layout", jsonLayoutPath)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "{\n  \"Code\": 42\n}\n", stdout)
+
+	var invalidLayoutPath = writeTestLayout(t, "invalid.yaml", "fields:\n  - name: code\n    type: int\n")
+
+	code, _, stderr = runTest("42", "decode", "-layout", invalidLayoutPath)
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "field 'code'")
+}
+
+func TestEncode(t *testing.T) {
+
+	var layoutPath = writeTestLayout(t, "layout.yaml", testLayoutYAML)
+
+	var input = `{"RecordType":"D","SampleId":"S1","Results":[{"TestCode":"61","Value":1}]}` + "\n" +
+		`[{"RecordType":"D","SampleId":"S2","Results":[{"TestCode":"62","Value":2}]}]`
+
+	code, stdout, stderr := runTest(input, "encode", "-layout", layoutPath, "-terminator", `\n`)
+	assert.Equal(t, 0, code, stderr)
+	assert.Equal(t, "D  S161001\n\nD  S262002\n\n", stdout)
+
+	code, _, stderr = runTest(`{"RecordType":"D","Unknown":1}`, "encode", "-layout", layoutPath)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "Unknown")
+}
+
+func TestDumpAndValidate(t *testing.T) {
+
+	var layoutPath = writeTestLayout(t, "layout.yaml", testLayoutYAML)
+
+	code, stdout, _ := runTest("DS1  61001\r\r", "dump", "-layout", layoutPath)
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "|61|                [0].Results[0].TestCode")
+
+	code, stdout, _ = runTest("DS1  61001\r\r", "validate", "-layout", layoutPath)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "valid: 1 record(s)\n", stdout)
+
+	code, _, stderr := runTest("DS1  61999\r\rDS2  62X02\r\r", "validate", "-layout", layoutPath)
+	assert.Equal(t, 1, code)
+	assert.Equal(t, 2, strings.Count(stderr, "\n"), stderr)
+	assert.Contains(t, stderr, "max")
+	assert.Contains(t, stderr, "of record 1")
+}
+
+func TestDecimalSeparator(t *testing.T) {
+
+	var layoutPath = writeTestLayout(t, "layout.json", `{"fields": [{"name": "Value", "type": "float64", "bin": ":8,notation:fixed,precision:2"}]}`)
+
+	code, stdout, stderr := runTest("1.234,50", "decode", "-layout", layoutPath, "-format", "ndjson", "-decimal", ",", "-thousands", ".")
+	assert.Equal(t, 0, code, stderr)
+	assert.Equal(t, `{"Value":1234.5}`+"\n", stdout)
+
+	code, stdout, stderr = runTest(`{"Value": 6.4}`, "encode", "-layout", layoutPath, "-decimal", ",")
+	assert.Equal(t, 0, code, stderr)
+	assert.Equal(t, "00006,40", stdout)
+
+	code, _, stderr = runTest("", "decode", "-layout", layoutPath, "-decimal", ",,")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "invalid -decimal")
+}
+
+func TestValueTypes(t *testing.T) {
+
+	var layoutPath = writeTestLayout(t, "layout.json", `{"fields": [
+		{"name": "Count", "type": "*int", "bin": ":2"},
+		{"name": "Amount", "type": "null[decimal]", "bin": ":6"},
+		{"name": "Result", "type": "qualified", "bin": ":5"}
+	]}`)
+
+	code, stdout, stderr := runTest("  006.40<00.5", "decode", "-layout", layoutPath, "-format", "ndjson")
+	assert.Equal(t, 0, code, stderr)
+	assert.Equal(t, `{"Count":null,"Amount":6.40,"Result":{"Qualifier":"\u003c","Value":0.5,"Raw":"\u003c00.5"}}`+"\n", stdout)
+
+	code, stdout, stderr = runTest("07      >0012", "decode", "-layout", layoutPath, "-format", "csv")
+	assert.Equal(t, 0, code, stderr)
+	assert.Equal(t, "Count,Amount,Result\n7,,>12\n", stdout)
+
+	code, stdout, stderr = runTest(`{"Count": 7, "Amount": 12.50, "Result": {"Qualifier": ">", "Value": 5}}`, "encode", "-layout", layoutPath)
+	assert.Equal(t, 0, code, stderr)
+	assert.Equal(t, "07012.50>005.", stdout)
+}
+
+// A reader failing the test if the input is read.
+type testUnreadInput struct {
+	t *testing.T
+}
+
+func (input testUnreadInput) Read([]byte) (int, error) {
+	input.t.Fatal("the input was read before the flags were checked")
+	return 0, nil
+}
+
+func TestFlags(t *testing.T) {
+
+	var layoutPath = writeTestLayout(t, "layout.yaml", testLayoutYAML)
+
+	for _, args := range [][]string{
+		{"decode", "-layout", layoutPath, "-format", "xml"},
+		{"decode", "-layout", layoutPath, "-timezone", "Europe/Nowhere"},
+		{"dump", "-layout", layoutPath, "-timezone", ""},
+		{"dump", "-layout", layoutPath, "-encoding", "ebcdic"},
+		{"validate", "-layout", filepath.Join(t.TempDir(), "missing.yaml"), "-timezone", "Mars/Olympus"}, // before the layout as well
+	} {
+		var stdout, stderr bytes.Buffer
+		var code = run(args, testUnreadInput{t: t}, &stdout, &stderr)
+		assert.Equal(t, 2, code, args)
+		assert.Contains(t, stderr.String(), "unknown -", args)
+	}
+
+	//-------------------------------------------------------------------------
+
+	code, stdout, stderr := runTest("DS1  61001\r\r", "dump", "-layout", layoutPath, "-encoding", "windows1252", "-timezone", "Europe/Berlin")
+	assert.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "|61|                [0].Results[0].TestCode")
+}
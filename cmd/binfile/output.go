@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// The output formats of the decode command.
+const (
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+	formatCSV    = "csv"
+)
+
+// Checks if 'format' is one of the output formats and returns a bool accordingly.
+func isKnownFormat(format string) bool {
+	return format == formatJSON || format == formatNDJSON || format == formatCSV
+}
+
+// Writes the decoded 'records' in the requested 'format'. With 'asArray', the records are written
+// as a JSON array - otherwise the one and only record as a JSON object. NDJSON and CSV are always line per record.
+func writeRecords(out io.Writer, format string, records []reflect.Value, asArray bool) error {
+
+	switch format {
+	case formatJSON:
+
+		var encoder = json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if !asArray {
+			return encoder.Encode(records[0].Interface())
+		}
+		var values = make([]interface{}, len(records))
+		for i, record := range records {
+			values[i] = record.Interface()
+		}
+		return encoder.Encode(values)
+
+	case formatNDJSON:
+
+		var encoder = json.NewEncoder(out)
+		for _, record := range records {
+			if err := encoder.Encode(record.Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case formatCSV:
+
+		if len(records) == 0 {
+			return nil
+		}
+		var writer = csv.NewWriter(out)
+		if err := writer.Write(csvColumns(records[0].Type(), "")); err != nil {
+			return err
+		}
+		for _, record := range records {
+			var row, err = csvValues(record)
+			if err != nil {
+				return err
+			}
+			if err = writer.Write(row); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+
+	return fmt.Errorf("unknown format '%s' - use %s, %s or %s", format, formatJSON, formatNDJSON, formatCSV)
+}
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// Returns the CSV header for a record type. Nested structs are flattened, ex.: "Outer.Inner".
+func csvColumns(recordType reflect.Type, prefix string) []string {
+	var columns []string
+	for i := 0; i < recordType.NumField(); i++ {
+		var field = recordType.Field(i)
+		if field.Type.Kind() == reflect.Struct && !field.Type.Implements(stringerType) { // not a value, ex.: a Decimal
+			columns = append(columns, csvColumns(field.Type, prefix+field.Name+".")...)
+			continue
+		}
+		columns = append(columns, prefix+field.Name)
+	}
+	return columns
+}
+
+// Returns the CSV cells for a record in the order of csvColumns. Arrays don't fit into a single cell, they are written as JSON.
+func csvValues(record reflect.Value) ([]string, error) {
+	var values []string
+	for i := 0; i < record.NumField(); i++ {
+		var field = record.Field(i)
+		switch {
+		case field.Kind() == reflect.Ptr:
+			if field.IsNil() {
+				values = append(values, "")
+			} else {
+				values = append(values, fmt.Sprint(field.Elem().Interface()))
+			}
+		case field.Kind() == reflect.Struct && !field.Type().Implements(stringerType):
+			var nested, err = csvValues(field)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, nested...)
+		case field.Kind() == reflect.Slice:
+			var encoded, err = json.Marshal(field.Interface())
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, string(encoded))
+		default:
+			values = append(values, fmt.Sprint(field.Interface()))
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,106 @@
+package binfile
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// A codeTable holds the bidirectional mapping between the values of a named Go type and their literals on the wire.
+type codeTable struct {
+	typeName string
+	toWire   map[interface{}]string
+	fromWire map[string]interface{}
+}
+
+var codeTables = map[reflect.Type]*codeTable{}
+var codeTablesMutex sync.RWMutex
+
+// Registers a code table for the named type 'T'. Every field of that type is then converted through the table:
+// the Go value is written as its wire literal and the wire literal is read back as the Go value.
+//
+// Registering a table for the same type again replaces the previous one.
+// Returns an error if 'T' is not a named type or if a wire literal is used for more than one value.
+func RegisterCodeTable[T comparable](table map[T]string) error {
+
+	var tableType = reflect.TypeOf((*T)(nil)).Elem()
+	if tableType.Name() == "" {
+		return newUnsupportedTypeError(tableType)
+	}
+
+	var newTable = &codeTable{
+		typeName: tableType.Name(),
+		toWire:   make(map[interface{}]string, len(table)),
+		fromWire: make(map[string]interface{}, len(table)),
+	}
+	for value, literal := range table {
+		if _, isDuplicate := newTable.fromWire[literal]; isDuplicate {
+			return newDuplicateCodeError(tableType.Name(), literal)
+		}
+		newTable.toWire[value] = literal
+		newTable.fromWire[literal] = value
+	}
+
+	codeTablesMutex.Lock()
+	defer codeTablesMutex.Unlock()
+	codeTables[tableType] = newTable
+
+	return nil
+}
+
+// Returns the code table registered for 'valueType' along with a bool which is true if found. (', ok' idiom)
+func getCodeTable(valueType reflect.Type) (*codeTable, bool) {
+	codeTablesMutex.RLock()
+	defer codeTablesMutex.RUnlock()
+	var table, isFound = codeTables[valueType]
+	return table, isFound
+}
+
+// Returns the Go value for the wire literal 'code'. The code is looked up as is first, then without surrounding spaces.
+// Gives an ErrorUnknownCode if neither is in the table.
+func (table *codeTable) decode(code string) (interface{}, error) {
+	if value, isFound := table.fromWire[code]; isFound {
+		return value, nil
+	}
+	if value, isFound := table.fromWire[strings.TrimSpace(code)]; isFound {
+		return value, nil
+	}
+	return nil, newUnknownCodeError(table.typeName, code)
+}
+
+// Returns the wire literal for the Go 'value' or an ErrorUnknownCode if it is not in the table.
+func (table *codeTable) encode(value interface{}) (string, error) {
+	if literal, isFound := table.toWire[value]; isFound {
+		return literal, nil
+	}
+	return "", newUnknownCodeError(table.typeName, fmt.Sprint(value))
+}
+
+// Stores the unknown 'code' in the string field named 'name' of the struct in 'structValue'.
+func setFallbackCode(structValue reflect.Value, name string, code string) error {
+	var fieldVal, isFieldFound = getFieldFromStruct(structValue, name)
+	if !isFieldFound {
+		return ErrorUnknownFieldName
+	}
+	if fieldVal.Kind() != reflect.String {
+		return newUnsupportedTypeError(fieldVal.Type())
+	}
+	if !fieldVal.CanSet() {
+		return ErrorAnnotatedFieldNotWritable
+	}
+	fieldVal.SetString(code)
+	return nil
+}
+
+// Returns the content of the string field named 'name' of the struct in 'structValue' to be written instead of an unknown code.
+func getFallbackCode(structValue reflect.Value, name string) (string, error) {
+	var fieldVal, isFieldFound = getFieldFromStruct(structValue, name)
+	if !isFieldFound {
+		return "", ErrorUnknownFieldName
+	}
+	if fieldVal.Kind() != reflect.String {
+		return "", newUnsupportedTypeError(fieldVal.Type())
+	}
+	return fieldVal.String(), nil
+}
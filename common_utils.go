@@ -1,7 +1,11 @@
 package binfile
 
 import (
+	"bytes"
+	"fmt"
 	"reflect"
+	"strings"
+	"unicode/utf8"
 )
 
 // Searches for a field in 'structValue' with the provided 'name' and returns the valid integer value from it or an error.
@@ -29,6 +33,49 @@ func resolveDynamicArraySize(structValue reflect.Value, name string) (int, error
 	return arraySize, nil
 }
 
+// Checks if the field in 'structValue' with the provided 'name' holds the 'expected' value and returns a bool accordingly.
+// Strings are compared with and without their surrounding spaces, values with a code table by their wire literal
+// and every other value by its default text representation.
+//
+// NOTE: Like the dynamic array size, this only works if the referenced field is already processed.
+func evaluateCondition(structValue reflect.Value, name string, expected string) (bool, error) {
+
+	var fieldVal, isFieldFound = getFieldFromStruct(structValue, name)
+	if !isFieldFound {
+		return false, ErrorUnknownFieldName
+	}
+
+	if table, hasCodeTable := getCodeTable(fieldVal.Type()); hasCodeTable {
+		literal, err := table.encode(fieldVal.Interface())
+		if err != nil {
+			return false, nil // an unknown code never matches
+		}
+		return literal == expected || strings.TrimSpace(literal) == expected, nil
+	}
+
+	if fieldVal.Kind() == reflect.String {
+		return fieldVal.String() == expected || strings.TrimSpace(fieldVal.String()) == expected, nil
+	}
+
+	return fmt.Sprint(fieldVal.Interface()) == expected, nil
+}
+
+// Returns the name of an array field's element for error messages. ex.: "TestResults[3]"
+func indexedFieldName(name string, index int) string {
+	return fmt.Sprintf("%s[%d]", name, index)
+}
+
+// Returns the path of the 'child' field within the 'parent' field. ex.: "TestResults[3]" and "Flags" to "TestResults[3].Flags"
+func joinFieldPath(parent string, child string) string {
+	if parent == "" {
+		return child
+	}
+	if strings.HasPrefix(child, "[") {
+		return parent + child
+	}
+	return parent + "." + child
+}
+
 // find a searchstring within an array of strings. only matches full
 // returns
 //   - true if the string is present
@@ -57,6 +104,18 @@ func advanceThroughTerminator(byteArray []byte, currentPos int, arrayTerminator
 	return currentPos, false
 }
 
+// Searches for the next 'arrayTerminator' from 'currentPos' on and returns the position right after it.
+// Returns the length of the byte array if there is no terminator left.
+func skipThroughTerminator(byteArray []byte, currentPos int, arrayTerminator string) int {
+	if arrayTerminator == "" || currentPos >= len(byteArray) {
+		return len(byteArray)
+	}
+	if i := bytes.Index(byteArray[currentPos:], []byte(arrayTerminator)); i >= 0 {
+		return currentPos + i + len(arrayTerminator)
+	}
+	return len(byteArray)
+}
+
 // Creates and adds the padding bytes of provided 'byteToUse' and of requested 'length' to the 'original' byte array.
 // Returns the padded byte array and it's new size.
 func appendPaddingBytes(original []byte, length int, byteToUse byte) ([]byte, int) {
@@ -67,3 +126,68 @@ func appendPaddingBytes(original []byte, length int, byteToUse byte) ([]byte, in
 	var temp = append(original, paddingBytes...)
 	return temp, len(temp)
 }
+
+// Returns 'value' padded with 'fill' to 'length' bytes according to the 'alignment': "left", "right" or "center".
+// Centered values get the odd fill byte on the right.
+func alignBytes(value []byte, length int, alignment string, fill byte) []byte {
+
+	var padding = length - len(value)
+	if padding <= 0 {
+		return value
+	}
+
+	var left = padding // right-aligned
+	switch alignment {
+	case "left":
+		left = 0
+	case "center":
+		left = padding / 2
+	}
+
+	var outBytes, _ = appendPaddingBytes(make([]byte, 0, length), left, fill)
+	outBytes = append(outBytes, value...)
+	outBytes, _ = appendPaddingBytes(outBytes, padding-left, fill)
+
+	return outBytes
+}
+
+// Returns 'value' without the 'fill' bytes on the side(s) it was padded on according to the 'alignment'. (see alignBytes)
+func trimAligned(value string, alignment string, fill byte) string {
+	switch alignment {
+	case "left":
+		return trimRightByte(value, fill)
+	case "center":
+		return trimLeftByte(trimRightByte(value, fill), fill)
+	}
+	return trimLeftByte(value, fill)
+}
+
+// Returns 'value' without the leading 'char' bytes. Unlike strings.TrimLeft, it works for any byte.
+func trimLeftByte(value string, char byte) string {
+	var start = 0
+	for start < len(value) && value[start] == char {
+		start++
+	}
+	return value[start:]
+}
+
+// Returns 'value' without the trailing 'char' bytes. Unlike strings.TrimRight, it works for any byte.
+func trimRightByte(value string, char byte) string {
+	var end = len(value)
+	for end > 0 && value[end-1] == char {
+		end--
+	}
+	return value[:end]
+}
+
+// Returns the first 'length' bytes of 'value' without cutting a UTF-8 character in half.
+func truncateBytes(value []byte, length int) []byte {
+	if len(value) <= length {
+		return value
+	}
+	var end = length
+	for end > 0 && !utf8.RuneStart(value[end]) {
+		end--
+	}
+	return value[:end]
+}
@@ -17,3 +17,34 @@ const TimezoneUTC Timezone = "UTC"
 const TimezoneEuropeBerlin Timezone = "Europe/Berlin"
 const TimezoneEuropeBudapest Timezone = "Europe/Budapest"
 const TimezoneEuropeLondon Timezone = "Europe/London"
+
+type Alignment string
+
+const AlignLeft Alignment = "left"
+const AlignRight Alignment = "right"
+const AlignCenter Alignment = "center"
+
+type Notation string
+
+const NotationFixed Notation = "fixed"
+const NotationScientific Notation = "sci"
+const NotationAuto Notation = "auto"
+
+type Rounding string
+
+const RoundingHalfUp Rounding = "half-up"
+const RoundingHalfEven Rounding = "half-even"
+const RoundingTruncate Rounding = "truncate"
+
+type Overflow string
+
+const OverflowError Overflow = "error"
+const OverflowClamp Overflow = "clamp"
+const OverflowFill Overflow = "fill"
+
+type Sign string
+
+const SignLeading Sign = "leading"
+const SignTrailing Sign = "trailing"
+const SignSpace Sign = "space"
+const SignNone Sign = "none"
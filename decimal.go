@@ -0,0 +1,159 @@
+package binfile
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// A Decimal is an exact decimal number, for values that must not pick up the binary rounding artifacts of floats.
+// It keeps the digits and the scale of the text it was read from: "6.40" is written back as "6.40", not "6.4".
+//
+// The number is read and written like a float in fixed notation - 'precision', 'forcesign', 'padspace', 'sign',
+// 'decimal' and the others apply. The zero value is 0 with a scale of 0.
+type Decimal struct {
+	unscaled *big.Int // nil for 0
+	scale    int      // the number of digits behind the point
+}
+
+var decimalType = reflect.TypeOf(Decimal{})
+
+// Returns the Decimal 'unscaled' * 10^-'scale', ex.: NewDecimal(640, 2) is 6.40.
+func NewDecimal(unscaled int64, scale int) Decimal {
+	var value = big.NewInt(unscaled)
+	if scale < 0 { // ex.: 64 * 10^1
+		value.Mul(value, pow10(-scale))
+		scale = 0
+	}
+	return Decimal{unscaled: value, scale: scale}
+}
+
+// Returns the Decimal for a text like "-12.340", "+.5" or "7" - the digits behind the point give its scale.
+// Gives a *strconv.NumError matching strconv.ErrSyntax for anything else.
+func ParseDecimal(text string) (Decimal, error) {
+
+	var digits, isNegative = text, false
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		digits, isNegative = digits[1:], digits[0] == '-'
+	}
+
+	var scale = 0
+	if point := strings.IndexByte(digits, '.'); point >= 0 {
+		scale = len(digits) - point - 1
+		digits = digits[:point] + digits[point+1:]
+	}
+
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return Decimal{}, &strconv.NumError{Func: "ParseDecimal", Num: text, Err: strconv.ErrSyntax}
+	}
+
+	var unscaled, _ = new(big.Int).SetString(digits, 10)
+	if isNegative {
+		unscaled.Neg(unscaled)
+	}
+
+	return Decimal{unscaled: unscaled, scale: scale}, nil
+}
+
+// Returns the digits of the number without the point, ex.: 640 for 6.40.
+func (d Decimal) Unscaled() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(d.unscaled)
+}
+
+// Returns the number of digits behind the point.
+func (d Decimal) Scale() int {
+	return d.scale
+}
+
+// Returns -1, 0 or +1 for a negative number, zero or a positive number.
+func (d Decimal) Sign() int {
+	return d.Unscaled().Sign()
+}
+
+// Compares the numbers regardless of their scale and returns -1, 0 or +1 if 'd' is less, equal or greater than 'other'.
+func (d Decimal) Cmp(other Decimal) int {
+	var left, right = d.Unscaled(), other.Unscaled()
+	if d.scale < other.scale {
+		left.Mul(left, pow10(other.scale-d.scale))
+	} else {
+		right.Mul(right, pow10(d.scale-other.scale))
+	}
+	return left.Cmp(right)
+}
+
+// Returns the nearest float64 to the number.
+func (d Decimal) Float64() float64 {
+	var value, _ = new(big.Rat).SetFrac(d.Unscaled(), pow10(d.scale)).Float64()
+	return value
+}
+
+// Returns the number with all digits of its scale, ex.: "-6.40".
+func (d Decimal) String() string {
+
+	var unscaled = d.Unscaled()
+	var digits = new(big.Int).Abs(unscaled).String()
+	if len(digits) <= d.scale { // a leading zero in front of the point
+		digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+	}
+
+	var text = digits
+	if d.scale > 0 {
+		text = digits[:len(digits)-d.scale] + "." + digits[len(digits)-d.scale:]
+	}
+	if unscaled.Sign() < 0 {
+		text = "-" + text
+	}
+
+	return text
+}
+
+// Encodes the number as JSON number with all digits of its scale, ex.: 6.40.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Decodes the number from a JSON number or string without an exponent, ex.: 6.40 or "6.40".
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	var text = string(data)
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+	var number, err = ParseDecimal(text)
+	if err != nil {
+		return &json.UnmarshalTypeError{Value: "number " + text, Type: decimalType}
+	}
+	*d = number
+	return nil
+}
+
+// Returns the number with 'scale' digits behind the point, rounded according to the 'rounding' mode. (see roundDigits)
+func (d Decimal) rescale(scale int, rounding string) Decimal {
+
+	var unscaled = d.Unscaled()
+	if scale >= d.scale {
+		return Decimal{unscaled: unscaled.Mul(unscaled, pow10(scale-d.scale)), scale: scale}
+	}
+
+	var digits = new(big.Int).Abs(unscaled).String()
+	if len(digits) <= d.scale {
+		digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+	}
+	digits, _ = roundDigits(digits, len(digits)-(d.scale-scale), rounding)
+
+	var rounded, _ = new(big.Int).SetString(digits, 10)
+	if unscaled.Sign() < 0 {
+		rounded.Neg(rounded)
+	}
+
+	return Decimal{unscaled: rounded, scale: scale}
+}
+
+// Returns 10^'exponent' for a non-negative exponent.
+func pow10(exponent int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
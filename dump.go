@@ -0,0 +1,191 @@
+package binfile
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const dumpBytesPerLine = 16
+
+type dumpSpanKind int
+
+const (
+	dumpSpanField dumpSpanKind = iota
+	dumpSpanGap
+	dumpSpanTerminator
+	dumpSpanError
+	dumpSpanUnparsed
+)
+
+// A dumpSpan is a byte range of the input and what it was read as.
+type dumpSpan struct {
+	start int
+	end   int
+	kind  dumpSpanKind
+	label string
+}
+
+// A dumpTrace records the byte ranges while unmarshaling for Dump.
+// All methods can be called on a nil trace, which doesn't record anything.
+type dumpTrace struct {
+	spans []dumpSpan
+	path  []string
+}
+
+// Makes the following spans belong to the nested field 'name' until leaveField is called.
+func (trace *dumpTrace) enterField(name string) {
+	if trace == nil {
+		return
+	}
+	trace.path = append(trace.path, name)
+}
+
+// Ends the nested field of the last enterField call.
+func (trace *dumpTrace) leaveField() {
+	if trace == nil {
+		return
+	}
+	trace.path = trace.path[:len(trace.path)-1]
+}
+
+// Records the bytes from 'start' to 'end' as the field 'name', or as a failed one if there is an 'err'.
+func (trace *dumpTrace) addField(name string, start int, end int, err error) {
+	if trace == nil {
+		return
+	}
+	var path = name
+	for i := len(trace.path) - 1; i >= 0; i-- {
+		path = joinFieldPath(trace.path[i], path)
+	}
+	if err != nil {
+		trace.spans = append(trace.spans, dumpSpan{start: start, end: end, kind: dumpSpanError, label: fmt.Sprintf("!! %s: %s", path, err.Error())})
+		return
+	}
+	trace.spans = append(trace.spans, dumpSpan{start: start, end: end, kind: dumpSpanField, label: path})
+}
+
+// Records the bytes from 'start' to 'end' as skipped by an absolute position.
+func (trace *dumpTrace) addGap(start int, end int) {
+	if trace == nil || start >= end {
+		return
+	}
+	trace.spans = append(trace.spans, dumpSpan{start: start, end: end, kind: dumpSpanGap, label: "<gap>"})
+}
+
+// Records the bytes from 'start' to 'end' as an array terminator.
+func (trace *dumpTrace) addTerminator(start int, end int) {
+	if trace == nil || start >= end {
+		return
+	}
+	trace.spans = append(trace.spans, dumpSpan{start: start, end: end, kind: dumpSpanTerminator, label: "<terminator>"})
+}
+
+// Accepts a byte array and an annotated struct or array of structs - or a pointer to one - describing its layout.
+// The input is read with the encoding, timezone and terminator like Unmarshal does.
+//
+// Returns the input as hex and ASCII side by side, each byte range labeled with the field it is read as.
+// Gaps of absolute positions, terminators, bytes that failed to parse and bytes that were not read are marked.
+// The input is always read in lenient mode and the provided 'target' is not changed.
+// For a 'target' that can't describe a layout, only a line with the error is returned.
+func Dump(inputBytes []byte, target interface{}, enc Encoding, tz Timezone, arrayTerminator string, options ...Option) string {
+
+	var targetType = reflect.TypeOf(target)
+	if targetType != nil && targetType.Kind() == reflect.Ptr {
+		targetType = targetType.Elem()
+	}
+	if targetType == nil || (targetType.Kind() != reflect.Struct && (targetType.Kind() != reflect.Slice || targetType.Elem().Kind() != reflect.Struct)) {
+		return fmt.Sprintf("!! %s\n", newUnsupportedTypeError(targetType).Error())
+	}
+
+	var opts = newOptions(options)
+	opts.lenient = true
+	opts.trace = &dumpTrace{}
+
+	var position, err = unmarshal(inputBytes, reflect.New(targetType).Interface(), enc, tz, arrayTerminator, opts)
+	if err != nil && len(opts.trace.spans) == 0 {
+		return fmt.Sprintf("!! %s\n", err.Error())
+	}
+
+	return renderDump(inputBytes, opts.trace.spans, position)
+}
+
+// Sorts the spans, labels the bytes that are not covered by any and formats them line by line.
+func renderDump(inputBytes []byte, spans []dumpSpan, position int) string {
+
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].start < spans[j].start
+	})
+
+	var completeSpans []dumpSpan
+	var covered = 0
+	for _, span := range spans {
+		if span.end > len(inputBytes) {
+			span.end = len(inputBytes)
+		}
+		if span.start < covered {
+			span.start = covered // overlapping ranges are shown once
+		}
+		if span.start > covered {
+			completeSpans = append(completeSpans, dumpSpan{start: covered, end: span.start, kind: dumpSpanUnparsed, label: "<unparsed>"})
+		}
+		if span.start >= span.end && span.kind != dumpSpanError {
+			continue
+		}
+		completeSpans = append(completeSpans, span)
+		if span.end > covered {
+			covered = span.end
+		}
+	}
+	if covered < len(inputBytes) {
+		var label = "<unparsed>"
+		if covered >= position {
+			label = "<unconsumed>"
+		}
+		completeSpans = append(completeSpans, dumpSpan{start: covered, end: len(inputBytes), kind: dumpSpanUnparsed, label: label})
+	}
+
+	var out strings.Builder
+	for _, span := range completeSpans {
+		if span.start >= span.end { // a field that failed without any bytes left
+			fmt.Fprintf(&out, "%08x  %-*s  %-*s  %s\n", span.start, dumpBytesPerLine*3-1, "", dumpBytesPerLine+2, "||", span.label)
+			continue
+		}
+		for lineStart := span.start; lineStart < span.end; lineStart += dumpBytesPerLine {
+			var lineEnd = lineStart + dumpBytesPerLine
+			if lineEnd > span.end {
+				lineEnd = span.end
+			}
+			var label = span.label
+			if lineStart > span.start {
+				label = "..."
+			}
+			fmt.Fprintf(&out, "%08x  %-*s  %-*s  %s\n", lineStart, dumpBytesPerLine*3-1, dumpHex(inputBytes[lineStart:lineEnd]), dumpBytesPerLine+2, "|"+dumpASCII(inputBytes[lineStart:lineEnd])+"|", label)
+		}
+	}
+
+	return out.String()
+}
+
+// Returns the bytes as space separated hex values.
+func dumpHex(byteArray []byte) string {
+	var hexValues = make([]string, len(byteArray))
+	for i, val := range byteArray {
+		hexValues[i] = fmt.Sprintf("%02x", val)
+	}
+	return strings.Join(hexValues, " ")
+}
+
+// Returns the bytes as text with a '.' for every non-printable byte.
+func dumpASCII(byteArray []byte) string {
+	var text = make([]byte, len(byteArray))
+	for i, val := range byteArray {
+		if val >= 0x20 && val < 0x7f {
+			text[i] = val
+		} else {
+			text[i] = '.'
+		}
+	}
+	return string(text)
+}
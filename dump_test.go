@@ -0,0 +1,63 @@
+package binfile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//
+//-Dump------------------------------------------------------------------------
+
+type testDumpInner struct {
+	TestCode string `bin:":2"`
+	Value    int    `bin:":3"`
+}
+
+type testDump struct {
+	RecordType string          `bin:":1"`
+	SampleId   string          `bin:"4:4"`
+	Results    []testDumpInner `bin:"array:terminator"`
+}
+
+func TestDump(t *testing.T) {
+
+	var data = []byte("Dxxx00476100162X0X\r\rjunk")
+
+	var dump = Dump(data, []testDump{}, EncodingUTF8, TimezoneUTC, "\r")
+	var lines = strings.Split(strings.TrimRight(dump, "\n"), "\n")
+
+	assert.Equal(t, []string{
+		"00000000  44                                               |D|                 [0].RecordType",
+		"00000001  78 78 78                                         |xxx|               <gap>",
+		"00000004  30 30 34 37                                      |0047|              [0].SampleId",
+		"00000008  36 31                                            |61|                [0].Results[0].TestCode",
+		"0000000a  30 30 31                                         |001|               [0].Results[0].Value",
+		"0000000d  36 32                                            |62|                [0].Results[1].TestCode",
+		"0000000f  58 30 58                                         |X0X|               !! [0].Results[1].Value: invalid int 'X0X' (3 bytes): invalid syntax",
+		"00000012  0d                                               |.|                 <terminator>",
+		"00000013  0d                                               |.|                 <terminator>",
+		"00000014  6a                                               |j|                 [1].RecordType",
+		"00000015  75 6e 6b                                         |unk|               <gap>",
+		"00000018                                                   ||                  !! [1].SampleId: reading out of bounds from position '24' to '28' (4 bytes) in input data of '24' bytes",
+	}, lines)
+
+	//-------------------------------------------------------------------------
+
+	var longField = Dump([]byte("0123456789abcdefghij"), &testDumpLong{}, EncodingWindows1252, TimezoneEuropeBerlin, "\r")
+
+	assert.Equal(t, "00000000  30 31 32 33 34 35 36 37 38 39 61 62 63 64 65 66  |0123456789abcdef|  Text\n"+
+		"00000010  67 68 69 6a                                      |ghij|              ...\n", longField)
+
+	//-------------------------------------------------------------------------
+
+	// no layout to read the input with
+	assert.Equal(t, "!! unsupported type 'nil'\n", Dump(data, nil, EncodingUTF8, TimezoneUTC, "\r"))
+	assert.Equal(t, "!! unsupported type 'string'\n", Dump(data, "", EncodingUTF8, TimezoneUTC, "\r"))
+	assert.Equal(t, "!! unsupported type '[]int'\n", Dump(data, &[]int{}, EncodingUTF8, TimezoneUTC, "\r"))
+}
+
+type testDumpLong struct {
+	Text string `bin:":20"`
+}
@@ -1,8 +1,11 @@
 package binfile
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 )
 
 // An ErrorUnsupportedType is returned when the processed field type is not supported by the implementation.
@@ -11,6 +14,12 @@ type ErrorUnsupportedType struct {
 }
 
 func (e *ErrorUnsupportedType) Error() string {
+	if e.InvalidType == nil {
+		return "unsupported type 'nil'"
+	}
+	if e.InvalidType.Name() == "" { // ex.: '[]int'
+		return fmt.Sprintf("unsupported type '%s'", e.InvalidType.String())
+	}
 	return fmt.Sprintf("unsupported type '%s'", e.InvalidType.Name())
 }
 
@@ -120,6 +129,82 @@ func newInvalidRelativeLengthError(err error) error {
 	return &ErrorInvalidRelativeLength{Err: err}
 }
 
+// An ErrorUnknownAnnotation is returned when a 'bin' tag contains an annotation that doesn't exist, ex.: a misspelled one.
+var ErrorUnknownAnnotation = fmt.Errorf("unknown annotation")
+
+// An ErrorDuplicateAnnotation is returned when a 'bin' tag contains an annotation more than once.
+var ErrorDuplicateAnnotation = fmt.Errorf("duplicate annotation")
+
+// An ErrorMalformedAnnotation is returned when an annotation is missing its value, has one it doesn't take
+// or its quotes aren't closed.
+var ErrorMalformedAnnotation = fmt.Errorf("malformed annotation")
+
+// An ErrorInvalidAnnotationValue is returned when the value of an annotation is not one it takes,
+// ex.: 'align:middle' or a 'fill' with more than one character.
+var ErrorInvalidAnnotationValue = fmt.Errorf("invalid annotation value")
+
+// An ErrorInvalidAnnotation is returned when the 'bin' tag of a field can't be parsed.
+// Check the underlying error for more information!
+type ErrorInvalidAnnotation struct {
+	Annotation string
+	Err        error
+}
+
+func (e *ErrorInvalidAnnotation) Error() string {
+	return fmt.Sprintf("invalid annotation '%s': %s", e.Annotation, e.Err.Error())
+}
+
+func (e *ErrorInvalidAnnotation) Is(target error) bool {
+	_, ok := target.(*ErrorInvalidAnnotation)
+	return ok
+}
+
+func (e *ErrorInvalidAnnotation) Unwrap() error {
+	return e.Err
+}
+
+func newInvalidAnnotationError(annotation string, err error) error {
+	return &ErrorInvalidAnnotation{Annotation: annotation, Err: err}
+}
+
+// An ErrorInvalidSign is returned when the sign of a number doesn't match the 'sign' annotation, ex.: a leading sign
+// for 'sign:trailing', a second sign or a negative number for 'sign:none'.
+type ErrorInvalidSign struct {
+	Value string
+	Sign  string
+}
+
+func (e *ErrorInvalidSign) Error() string {
+	return fmt.Sprintf("invalid sign in '%s' for 'sign:%s'", e.Value, e.Sign)
+}
+
+func (e *ErrorInvalidSign) Is(target error) bool {
+	_, ok := target.(*ErrorInvalidSign)
+	return ok
+}
+
+func newInvalidSignError(value string, sign string) error {
+	return &ErrorInvalidSign{Value: value, Sign: sign}
+}
+
+// An ErrorInvalidQualifier is returned when a QualifiedNumber with an unknown qualifier is marshaled.
+type ErrorInvalidQualifier struct {
+	Qualifier string
+}
+
+func (e *ErrorInvalidQualifier) Error() string {
+	return fmt.Sprintf("invalid qualifier '%s'", e.Qualifier)
+}
+
+func (e *ErrorInvalidQualifier) Is(target error) bool {
+	_, ok := target.(*ErrorInvalidQualifier)
+	return ok
+}
+
+func newInvalidQualifierError(qualifier string) error {
+	return &ErrorInvalidQualifier{Qualifier: qualifier}
+}
+
 // An ErrorIntConversionOverflow is returned when you try to convert a 64 bit value on a 32 bit system.
 var ErrorIntConversionOverflow = fmt.Errorf("int conversion overflow 32 vs 64 bit system")
 
@@ -188,6 +273,63 @@ func newInvalidValueLengthError(value string, length int) error {
 	return &ErrorInvalidValueLength{Value: value, Length: length}
 }
 
+// An ErrorNotANumber is matched by an ErrorInvalidNumber when the text is not a number of the target kind.
+var ErrorNotANumber = fmt.Errorf("not a number")
+
+// An ErrorUnexpectedSeparator is the cause of an ErrorInvalidNumber when a float uses another decimal separator than '.',
+// but contains a '.' that isn't the thousands separator either. The ErrorInvalidNumber matches ErrorNotANumber as well.
+var ErrorUnexpectedSeparator = fmt.Errorf("'.' is neither the decimal nor the thousands separator")
+
+// An ErrorNumberOutOfRange is matched by an ErrorInvalidNumber when the number doesn't fit into the target kind.
+var ErrorNumberOutOfRange = fmt.Errorf("number out of range")
+
+// An ErrorInvalidNumber is returned when the text read for an int, float32, float64 or Decimal field can't be converted.
+// Check the reason with errors.Is against ErrorNotANumber or ErrorNumberOutOfRange.
+type ErrorInvalidNumber struct {
+	Value    string
+	Length   int
+	Kind     reflect.Kind
+	TypeName string // the type for the message, ex.: "float32" or "decimal" for a Decimal field
+	Err      error
+}
+
+func (e *ErrorInvalidNumber) Error() string {
+	var cause = e.Err
+	var numErr *strconv.NumError
+	if errors.As(cause, &numErr) {
+		cause = numErr.Err // the text is already part of the message
+	}
+	var typeName = e.TypeName
+	if typeName == "" {
+		typeName = e.Kind.String()
+	}
+	return fmt.Sprintf("invalid %s '%s' (%d bytes): %s", typeName, e.Value, e.Length, cause.Error())
+}
+
+func (e *ErrorInvalidNumber) Is(target error) bool {
+	switch target {
+	case ErrorNotANumber:
+		return errors.Is(e.Err, strconv.ErrSyntax) || errors.Is(e.Err, ErrorUnexpectedSeparator)
+	case ErrorNumberOutOfRange:
+		return errors.Is(e.Err, strconv.ErrRange)
+	}
+	_, ok := target.(*ErrorInvalidNumber)
+	return ok
+}
+
+func (e *ErrorInvalidNumber) Unwrap() error {
+	return e.Err
+}
+
+func newInvalidNumberError(value string, kind reflect.Kind, err error) error {
+	return &ErrorInvalidNumber{Value: value, Length: len(value), Kind: kind, TypeName: kind.String(), Err: err}
+}
+
+// Same as newInvalidNumberError for the number types of this package, named 'typeName' in the message.
+func newInvalidNumberTypeError(value string, typeName string, err error) error {
+	return &ErrorInvalidNumber{Value: value, Length: len(value), Kind: reflect.Struct, TypeName: typeName, Err: err}
+}
+
 // An ErrorInvalidOffset is returned when the annotated absolute position...
 // is lower than the current position in the byte array.
 type ErrorInvalidOffset struct {
@@ -228,16 +370,96 @@ func newReadingOutOfBoundsError(fromPos, toPos, maxLength int) error {
 	return &ErrorReadingOutOfBounds{FromPos: fromPos, ToPos: toPos, MaxLength: maxLength}
 }
 
+// An ErrorTrailingBytes is returned in strict mode when a record doesn't end at the end of its layout,
+// ex.: there are more bytes after the last field or before the terminator of a record in a top-level array.
+type ErrorTrailingBytes struct {
+	Position int
+	Surplus  []byte
+}
+
+func (e *ErrorTrailingBytes) Error() string {
+	return fmt.Sprintf("found %d unconsumed bytes at position '%d': %q", len(e.Surplus), e.Position, e.Surplus)
+}
+
+func (e *ErrorTrailingBytes) Is(target error) bool {
+	_, ok := target.(*ErrorTrailingBytes)
+	return ok
+}
+
+func newTrailingBytesError(position int, surplus []byte) error {
+	return &ErrorTrailingBytes{Position: position, Surplus: surplus}
+}
+
+// The limits of an ErrorLimitExceeded.
+const (
+	LimitArrayLength = "array length"
+	LimitDepth       = "depth"
+	LimitRecordBytes = "record bytes"
+)
+
+// An ErrorLimitExceeded is returned when the input exceeds a limit set with MaxArrayLength, MaxDepth or MaxRecordBytes.
+type ErrorLimitExceeded struct {
+	Limit  string
+	Max    int
+	Actual int
+}
+
+func (e *ErrorLimitExceeded) Error() string {
+	return fmt.Sprintf("%s of '%d' exceeds the limit of '%d'", e.Limit, e.Actual, e.Max)
+}
+
+func (e *ErrorLimitExceeded) Is(target error) bool {
+	_, ok := target.(*ErrorLimitExceeded)
+	return ok
+}
+
+func newLimitExceededError(limit string, max int, actual int) error {
+	return &ErrorLimitExceeded{Limit: limit, Max: max, Actual: actual}
+}
+
+// An ErrorInternalPanic is returned instead of a panic that occurred while unmarshaling.
+// This is always a bug - please report it with the input, the target type and the stack trace.
+type ErrorInternalPanic struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *ErrorInternalPanic) Error() string {
+	return fmt.Sprintf("internal error: %v", e.Value)
+}
+
+func (e *ErrorInternalPanic) Is(target error) bool {
+	_, ok := target.(*ErrorInternalPanic)
+	return ok
+}
+
+func newInternalPanicError(value interface{}, stack []byte) error {
+	return &ErrorInternalPanic{Value: value, Stack: stack}
+}
+
 // An ErrorProcessingField is returned when something went wrong while processing the field.
 // Check the underlying error for more information!
+//
+// Errors of nested fields are not wrapped once per struct level. Instead, the error of the innermost field
+// is returned with the full path to it, ex.: "TestResults[3].Flags", and its absolute byte offset in the
+// input (unmarshaling) or output (marshaling). For top-level arrays, the record index is set as well, otherwise it's -1.
 type ErrorProcessingField struct {
 	FieldName   string
+	FieldPath   string
 	Annotations string
+	Offset      int
+	RecordIndex int
 	Err         error
 }
 
 func (e *ErrorProcessingField) Error() string {
-	return fmt.Sprintf("error processing field '%s' `%s`: %s", e.FieldName, e.Annotations, e.Err.Error())
+	if e.FieldPath == "" {
+		return fmt.Sprintf("error processing record %d at byte %d: %s", e.RecordIndex, e.Offset, e.Err.Error())
+	}
+	if e.RecordIndex >= 0 {
+		return fmt.Sprintf("error processing field '%s' `%s` of record %d at byte %d: %s", e.FieldPath, e.Annotations, e.RecordIndex, e.Offset, e.Err.Error())
+	}
+	return fmt.Sprintf("error processing field '%s' `%s` at byte %d: %s", e.FieldPath, e.Annotations, e.Offset, e.Err.Error())
 }
 
 func (e *ErrorProcessingField) Is(target error) bool {
@@ -249,8 +471,78 @@ func (e *ErrorProcessingField) Unwrap() error {
 	return e.Err
 }
 
-func newProcessingFieldError(fieldName string, annotations string, err error) error {
-	return &ErrorProcessingField{FieldName: fieldName, Annotations: annotations, Err: err}
+func newProcessingFieldError(fieldName string, annotations string, offset int, err error) error {
+	switch typedErr := err.(type) {
+	case *ErrorCollection:
+		var wrappedErrors = make([]error, len(typedErr.Errors))
+		for i, collectedErr := range typedErr.Errors {
+			wrappedErrors[i] = newProcessingFieldError(fieldName, annotations, offset, collectedErr)
+		}
+		return newErrorCollection(wrappedErrors)
+
+	case *ErrorProcessingField:
+		// keep the innermost field and only extend the path
+		var nestedErr = *typedErr
+		nestedErr.FieldPath = joinFieldPath(fieldName, nestedErr.FieldPath)
+		return &nestedErr
+	}
+
+	var baseName = fieldName
+	if i := strings.Index(baseName, "["); i >= 0 {
+		baseName = baseName[:i] // an element of a primitive array
+	}
+	return &ErrorProcessingField{FieldName: baseName, FieldPath: fieldName, Annotations: annotations, Offset: offset, RecordIndex: -1, Err: err}
+}
+
+// Moves the errors of the record with 'recordIndex' in a top-level array by the record's 'recordOffset'.
+// Errors that don't belong to a field are wrapped in an ErrorProcessingField without a path.
+func withRecordPosition(err error, recordIndex int, recordOffset int) error {
+	switch typedErr := err.(type) {
+	case nil:
+		return nil
+
+	case *ErrorCollection:
+		var movedErrors = make([]error, len(typedErr.Errors))
+		for i, collectedErr := range typedErr.Errors {
+			movedErrors[i] = withRecordPosition(collectedErr, recordIndex, recordOffset)
+		}
+		return newErrorCollection(movedErrors)
+
+	case *ErrorProcessingField:
+		var movedErr = *typedErr
+		movedErr.RecordIndex = recordIndex
+		movedErr.Offset += recordOffset
+		return &movedErr
+	}
+
+	return &ErrorProcessingField{Offset: recordOffset, RecordIndex: recordIndex, Err: err}
+}
+
+// Returns the full path of the field an error occurred in, ex.: "TestResults[3].Flags", along with a bool which is true if found. (', ok' idiom)
+func FieldPathOf(err error) (string, bool) {
+	var errProcessingField *ErrorProcessingField
+	if errors.As(err, &errProcessingField) {
+		return errProcessingField.FieldPath, true
+	}
+	return "", false
+}
+
+// Returns the absolute byte offset an error occurred at along with a bool which is true if found. (', ok' idiom)
+func OffsetOf(err error) (int, bool) {
+	var errProcessingField *ErrorProcessingField
+	if errors.As(err, &errProcessingField) {
+		return errProcessingField.Offset, true
+	}
+	return -1, false
+}
+
+// Returns the index of the record in a top-level array an error occurred in along with a bool which is true if found. (', ok' idiom)
+func RecordIndexOf(err error) (int, bool) {
+	var errProcessingField *ErrorProcessingField
+	if errors.As(err, &errProcessingField) && errProcessingField.RecordIndex >= 0 {
+		return errProcessingField.RecordIndex, true
+	}
+	return -1, false
 }
 
 // An ErrorMissingAddressAnnotation is returned when a non-struct field is missing the address annotation.
@@ -258,3 +550,281 @@ var ErrorMissingAddressAnnotation = fmt.Errorf("non-struct field must have addre
 
 // An ErrorMissingArrayAnnotation is returned when an array field is missing the 'array' annotation.
 var ErrorMissingArrayAnnotation = fmt.Errorf("array fields must have an 'array' annotation")
+
+// An ErrorUnknownCode is returned when a value of a type with a registered code table
+// has no entry in the table - on the wire or in Go.
+type ErrorUnknownCode struct {
+	TypeName string
+	Code     string
+}
+
+func (e *ErrorUnknownCode) Error() string {
+	return fmt.Sprintf("unknown code '%s' for type '%s'", e.Code, e.TypeName)
+}
+
+func (e *ErrorUnknownCode) Is(target error) bool {
+	_, ok := target.(*ErrorUnknownCode)
+	return ok
+}
+
+func newUnknownCodeError(typeName string, code string) error {
+	return &ErrorUnknownCode{TypeName: typeName, Code: code}
+}
+
+// An ErrorDuplicateCode is returned when a code table uses the same wire literal for more than one value.
+type ErrorDuplicateCode struct {
+	TypeName string
+	Code     string
+}
+
+func (e *ErrorDuplicateCode) Error() string {
+	return fmt.Sprintf("duplicate code '%s' in code table for type '%s'", e.Code, e.TypeName)
+}
+
+func (e *ErrorDuplicateCode) Is(target error) bool {
+	_, ok := target.(*ErrorDuplicateCode)
+	return ok
+}
+
+func newDuplicateCodeError(typeName string, code string) error {
+	return &ErrorDuplicateCode{TypeName: typeName, Code: code}
+}
+
+// An ErrorInvalidFallback is returned when the field referenced by the 'fallback' annotation can not hold the unknown code.
+// Check the underlying error for more information!
+type ErrorInvalidFallback struct {
+	FieldName string
+	Err       error
+}
+
+func (e *ErrorInvalidFallback) Error() string {
+	return fmt.Sprintf("invalid fallback field '%s': %s", e.FieldName, e.Err.Error())
+}
+
+func (e *ErrorInvalidFallback) Is(target error) bool {
+	_, ok := target.(*ErrorInvalidFallback)
+	return ok
+}
+
+func (e *ErrorInvalidFallback) Unwrap() error {
+	return e.Err
+}
+
+func newInvalidFallbackError(fieldName string, err error) error {
+	return &ErrorInvalidFallback{FieldName: fieldName, Err: err}
+}
+
+// An ErrorMalformedCondition is returned when the 'if' annotation doesn't have the form 'if:<field_name>=<value>'.
+var ErrorMalformedCondition = fmt.Errorf("condition must have the form 'if:<field_name>=<value>'")
+
+// An ErrorInvalidCondition is returned when something went wrong while evaluating the 'if' annotation.
+// Check the underlying error for more information!
+type ErrorInvalidCondition struct {
+	Condition string
+	Err       error
+}
+
+func (e *ErrorInvalidCondition) Error() string {
+	return fmt.Sprintf("invalid condition '%s': %s", e.Condition, e.Err.Error())
+}
+
+func (e *ErrorInvalidCondition) Is(target error) bool {
+	_, ok := target.(*ErrorInvalidCondition)
+	return ok
+}
+
+func (e *ErrorInvalidCondition) Unwrap() error {
+	return e.Err
+}
+
+func newInvalidConditionError(condition string, err error) error {
+	return &ErrorInvalidCondition{Condition: condition, Err: err}
+}
+
+// An ErrorInvalidDefault is returned when the literal of the 'default' annotation can't be converted into the field's type.
+// Check the underlying error for more information!
+type ErrorInvalidDefault struct {
+	Default string
+	Err     error
+}
+
+func (e *ErrorInvalidDefault) Error() string {
+	return fmt.Sprintf("invalid default value '%s': %s", e.Default, e.Err.Error())
+}
+
+func (e *ErrorInvalidDefault) Is(target error) bool {
+	_, ok := target.(*ErrorInvalidDefault)
+	return ok
+}
+
+func (e *ErrorInvalidDefault) Unwrap() error {
+	return e.Err
+}
+
+func newInvalidDefaultError(defaultLiteral string, err error) error {
+	return &ErrorInvalidDefault{Default: defaultLiteral, Err: err}
+}
+
+// An ErrorValidation is returned when a value violates one of the validation annotations.
+type ErrorValidation struct {
+	Rule      string
+	Parameter string
+	Value     string
+}
+
+func (e *ErrorValidation) Error() string {
+	if e.Parameter == "" {
+		return fmt.Sprintf("value '%s' violates '%s'", e.Value, e.Rule)
+	}
+	return fmt.Sprintf("value '%s' violates '%s:%s'", e.Value, e.Rule, e.Parameter)
+}
+
+func (e *ErrorValidation) Is(target error) bool {
+	_, ok := target.(*ErrorValidation)
+	return ok
+}
+
+func newValidationError(rule string, parameter string, value string) error {
+	return &ErrorValidation{Rule: rule, Parameter: parameter, Value: value}
+}
+
+// An ErrorInvalidValidationRule is returned when the parameter of a validation annotation is invalid.
+// Check the underlying error for more information!
+type ErrorInvalidValidationRule struct {
+	Annotation string
+	Err        error
+}
+
+func (e *ErrorInvalidValidationRule) Error() string {
+	return fmt.Sprintf("invalid validation annotation '%s': %s", e.Annotation, e.Err.Error())
+}
+
+func (e *ErrorInvalidValidationRule) Is(target error) bool {
+	_, ok := target.(*ErrorInvalidValidationRule)
+	return ok
+}
+
+func (e *ErrorInvalidValidationRule) Unwrap() error {
+	return e.Err
+}
+
+func newInvalidValidationRuleError(annotation string, err error) error {
+	return &ErrorInvalidValidationRule{Annotation: annotation, Err: err}
+}
+
+// An ErrorCollection is returned when more than one problem is reported at once.
+// It unwraps to all of them, the same way as the result of errors.Join does. Its Is and As methods
+// check them as well, for toolchains before Go 1.20 which don't unwrap to more than one error.
+type ErrorCollection struct {
+	Errors []error
+}
+
+func (e *ErrorCollection) Error() string {
+	var messages = make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (e *ErrorCollection) Is(target error) bool {
+	if _, ok := target.(*ErrorCollection); ok {
+		return true
+	}
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *ErrorCollection) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *ErrorCollection) Unwrap() []error {
+	return e.Errors
+}
+
+func newErrorCollection(errs []error) error {
+	return &ErrorCollection{Errors: errs}
+}
+
+// An ErrorInvalidFieldName is returned when the name of a schema field can't be the name of an exported struct field.
+var ErrorInvalidFieldName = fmt.Errorf("field names have to start with an upper case letter and can only contain letters, digits and '_'")
+
+// An ErrorDuplicateFieldName is returned when a schema has more than one field with the same name on the same level.
+var ErrorDuplicateFieldName = fmt.Errorf("duplicate field name")
+
+// An ErrorUnknownFieldType is returned when the type of a schema field is not supported.
+var ErrorUnknownFieldType = fmt.Errorf("unknown field type")
+
+// An ErrorInvalidNestedFields is returned when a schema struct has no fields or another type has some.
+var ErrorInvalidNestedFields = fmt.Errorf("only the types 'struct' and '[]struct' have fields and they need at least one")
+
+// An ErrorInvalidSchema is returned when a field of a Schema can't be processed.
+// Check the underlying error for more information!
+type ErrorInvalidSchema struct {
+	FieldPath string
+	Err       error
+}
+
+func (e *ErrorInvalidSchema) Error() string {
+	return fmt.Sprintf("invalid schema field '%s': %s", e.FieldPath, e.Err.Error())
+}
+
+func (e *ErrorInvalidSchema) Is(target error) bool {
+	_, ok := target.(*ErrorInvalidSchema)
+	return ok
+}
+
+func (e *ErrorInvalidSchema) Unwrap() error {
+	return e.Err
+}
+
+func newInvalidSchemaError(fieldPath string, err error) error {
+	return &ErrorInvalidSchema{FieldPath: fieldPath, Err: err}
+}
+
+// An ErrorFieldTypeMismatch is returned when a layout is registered for a struct with a field of another type.
+var ErrorFieldTypeMismatch = fmt.Errorf("the type of the struct field doesn't match the layout")
+
+// An ErrorFieldOrderMismatch is returned when a layout is registered for a struct with the fields in another order.
+var ErrorFieldOrderMismatch = fmt.Errorf("the fields of the layout have to be in the order of the struct fields")
+
+// An ErrorUnsupportedPosition is returned when a layout places a field without a length at an absolute position.
+var ErrorUnsupportedPosition = fmt.Errorf("an absolute position is only supported for fields with a length")
+
+// An ErrorSchemaValueMismatch is returned when a value to marshal with a Schema doesn't fit the type of its field.
+var ErrorSchemaValueMismatch = fmt.Errorf("value doesn't match the type of the field")
+
+// An ErrorInvalidSchemaValue is returned when a value to marshal with a Schema can't be used.
+// Check the underlying error for more information!
+type ErrorInvalidSchemaValue struct {
+	FieldPath string
+	Value     interface{}
+	Err       error
+}
+
+func (e *ErrorInvalidSchemaValue) Error() string {
+	return fmt.Sprintf("invalid value '%v' (%T) for field '%s': %s", e.Value, e.Value, e.FieldPath, e.Err.Error())
+}
+
+func (e *ErrorInvalidSchemaValue) Is(target error) bool {
+	_, ok := target.(*ErrorInvalidSchemaValue)
+	return ok
+}
+
+func (e *ErrorInvalidSchemaValue) Unwrap() error {
+	return e.Err
+}
+
+func newInvalidSchemaValueError(fieldPath string, value interface{}, err error) error {
+	return &ErrorInvalidSchemaValue{FieldPath: fieldPath, Value: value, Err: err}
+}
@@ -0,0 +1,91 @@
+package binfile
+
+import (
+	"errors"
+	"testing"
+)
+
+// Unmarshals 'data' into a new target of every mode and fails if a panic had to be recovered.
+// A panic is always a bug: malformed input has to result in an error.
+func fuzzUnmarshal(t *testing.T, data []byte, newTarget func() interface{}) {
+
+	var optionSets = [][]Option{
+		{},
+		{Lenient()},
+		{Strict()},
+		{Lenient(), Strict()},
+	}
+
+	for _, optionSet := range optionSets {
+		var position, err = Unmarshal(data, newTarget(), EncodingUTF8, TimezoneUTC, "\r", optionSet...)
+
+		var errInternalPanic *ErrorInternalPanic
+		if errors.As(err, &errInternalPanic) {
+			t.Fatalf("unmarshaling %q panicked: %v\n%s", data, errInternalPanic.Value, errInternalPanic.Stack)
+		}
+		if err == nil && (position < 0 || position > len(data)) {
+			t.Fatalf("unmarshaling %q returned position %d outside of the input", data, position)
+		}
+	}
+
+	Dump(data, newTarget(), EncodingUTF8, TimezoneUTC, "\r") // must not panic either
+}
+
+func FuzzUnmarshalGeneralStructure(f *testing.F) {
+
+	f.Add([]byte("D 03116506 044760722905768    E61     6.40  62      935  "))
+	f.Add([]byte("D 03116506 044760722905768    E61     6.40  62      935  \u000DD 03116507 044860722905758    E61     6.86  62      883  "))
+	f.Add([]byte("D 03116506 0447"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzUnmarshal(t, data, func() interface{} { return &testGeneralStructureUnmarshal{} })
+		fuzzUnmarshal(t, data, func() interface{} { return &[]testGeneralStructureUnmarshal{} })
+		fuzzUnmarshal(t, data, func() interface{} { return &testMultipleRecordsUnmarshal{} })
+	})
+}
+
+func FuzzUnmarshalTopLevelArray(f *testing.F) {
+
+	f.Add([]byte("ABCDEFxx  \r123456xx  \r"))
+	f.Add([]byte("ABCDEF"))
+	f.Add([]byte("\r\r\r"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzUnmarshal(t, data, func() interface{} { return &[]testTopLevelArrayInnerUnmarshal{} })
+		fuzzUnmarshal(t, data, func() interface{} { return &[]testValidAddressingUnmarshal{} })
+	})
+}
+
+func FuzzUnmarshalArrays(f *testing.F) {
+
+	f.Add([]byte("1234\r0123\r"))
+	f.Add([]byte("AA0BB1CC20123"))
+	f.Add([]byte("A3-1012"))
+	f.Add([]byte("D61001123\r"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzUnmarshal(t, data, func() interface{} { return &testNestedStructUnmarshal{} })
+		fuzzUnmarshal(t, data, func() interface{} { return &testArrayUnmarshal{} })
+		fuzzUnmarshal(t, data, func() interface{} { return &testFixedSizeArrayUnmarshal{} })
+		fuzzUnmarshal(t, data, func() interface{} { return &testDynamicArrayUnmarshal{} })
+		fuzzUnmarshal(t, data, func() interface{} { return &testErrorPositionUnmarshal{} })
+	})
+}
+
+func FuzzUnmarshalFieldAnnotations(f *testing.F) {
+
+	f.Add([]byte("Q00012L1123"))
+	f.Add([]byte("   \x00\x00\x00012      "))
+	f.Add([]byte("S123  00016100  "))
+	f.Add([]byte("1X3001.50-  3.1"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzUnmarshal(t, data, func() interface{} { return &testConditionalUnmarshal{} })
+		fuzzUnmarshal(t, data, func() interface{} { return &testDefaultUnmarshal{} })
+		fuzzUnmarshal(t, data, func() interface{} { return &testValidationUnmarshal{} })
+		fuzzUnmarshal(t, data, func() interface{} { return &testInvalidNumberUnmarshal{} })
+		fuzzUnmarshal(t, data, func() interface{} { return &testIntUnmarshal{} })
+		fuzzUnmarshal(t, data, func() interface{} { return &testFloatUnmarshal{} })
+		fuzzUnmarshal(t, data, func() interface{} { return &testStringUnmarshal{} })
+	})
+}
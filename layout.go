@@ -0,0 +1,446 @@
+package binfile
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// A LayoutBuilder defines a layout in code instead of with 'bin' tags, ex.:
+//
+//	var result = binfile.Layout().
+//		Field("TestCode", binfile.String(2)).
+//		Field("TestResult", binfile.Float32(9).Padspace())
+//
+//	var message = binfile.Layout().
+//		Field("RecordType", binfile.String(2)).
+//		Field("SampleId", binfile.String(11).At(15).Trim()).
+//		Array("TestResults", binfile.Terminated(), result)
+//
+// The layout is turned into the same annotations as the 'bin' tags. It can be used for maps with Schema
+// or registered for a struct type with RegisterLayout.
+type LayoutBuilder struct {
+	records bool
+	fields  []SchemaField
+	err     error // the first field that can't be built, returned by Schema and RegisterLayout
+}
+
+// A FieldSpec is the type and the annotations of a field in a LayoutBuilder.
+// Create it with String, Int, Float32, Float64, DecimalField, QualifiedField or Nested and add annotations with its methods.
+type FieldSpec struct {
+	typeName    string
+	position    int
+	length      int
+	annotations []string
+	nested      *LayoutBuilder
+}
+
+// An ArraySize defines how the number of elements of an array is determined.
+// Create it with Terminated, Fixed or SizedBy.
+type ArraySize struct {
+	annotation string
+}
+
+// An ArrayElement is the type of the elements of an array in a LayoutBuilder: a FieldSpec or a nested *LayoutBuilder.
+type ArrayElement interface {
+	elementSpec() FieldSpec
+}
+
+// Starts a new layout.
+func Layout() *LayoutBuilder {
+	return &LayoutBuilder{}
+}
+
+// Makes the layout a top-level array of records separated by the terminator. Only used by Schema.
+func (b *LayoutBuilder) Records() *LayoutBuilder {
+	b.records = true
+	return b
+}
+
+// Adds a field with the type and annotations in 'spec'.
+func (b *LayoutBuilder) Field(name string, spec FieldSpec) *LayoutBuilder {
+	b.checkSpec(name, spec)
+	b.fields = append(b.fields, spec.schemaField(name, "", ""))
+	return b
+}
+
+// Adds a nested struct with the fields of 'layout'. Same as Field with Nested(layout).
+func (b *LayoutBuilder) Struct(name string, layout *LayoutBuilder) *LayoutBuilder {
+	return b.Field(name, Nested(layout))
+}
+
+// Adds an array with the elements in 'element', either a FieldSpec or a nested *LayoutBuilder.
+func (b *LayoutBuilder) Array(name string, size ArraySize, element ArrayElement) *LayoutBuilder {
+	b.checkSpec(name, element.elementSpec())
+	b.fields = append(b.fields, element.elementSpec().schemaField(name, "[]", size.annotation))
+	return b
+}
+
+func (b *LayoutBuilder) elementSpec() FieldSpec {
+	return Nested(b)
+}
+
+// Keeps the first error of the layout: an absolute position of a field without a length - nested structs
+// and arrays of them - or an error of the nested layout in 'spec'.
+func (b *LayoutBuilder) checkSpec(name string, spec FieldSpec) {
+	if b.err != nil {
+		return
+	}
+	if spec.position >= 0 && spec.length < 0 {
+		b.err = newInvalidSchemaError(name, ErrorUnsupportedPosition)
+	} else if errInvalidSchema, isInvalid := spec.nestedError(); isInvalid {
+		b.err = newInvalidSchemaError(joinFieldPath(name, errInvalidSchema.FieldPath), errInvalidSchema.Err)
+	}
+}
+
+// Returns the error of the nested layout of the spec along with a bool which is true if there is one. (', ok' idiom)
+func (f FieldSpec) nestedError() (*ErrorInvalidSchema, bool) {
+	if f.nested == nil || f.nested.err == nil {
+		return nil, false
+	}
+	return f.nested.err.(*ErrorInvalidSchema), true
+}
+
+// Returns the layout as Schema for decoding into and encoding from maps.
+// Returns an ErrorInvalidSchema if a field can't be processed.
+func (b *LayoutBuilder) Schema() (*Schema, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	var schema = &Schema{Records: b.records, Fields: b.fields}
+	if _, err := schema.RecordType(); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// A string field of 'length' bytes.
+func String(length int) FieldSpec {
+	return FieldSpec{typeName: "string", position: -1, length: length}
+}
+
+// An int field of 'length' bytes.
+func Int(length int) FieldSpec {
+	return FieldSpec{typeName: "int", position: -1, length: length}
+}
+
+// A float32 field of 'length' bytes.
+func Float32(length int) FieldSpec {
+	return FieldSpec{typeName: "float32", position: -1, length: length}
+}
+
+// A float64 field of 'length' bytes.
+func Float64(length int) FieldSpec {
+	return FieldSpec{typeName: "float64", position: -1, length: length}
+}
+
+// A Decimal field of 'length' bytes.
+func DecimalField(length int) FieldSpec {
+	return FieldSpec{typeName: "decimal", position: -1, length: length}
+}
+
+// A QualifiedNumber field of 'length' bytes.
+func QualifiedField(length int) FieldSpec {
+	return FieldSpec{typeName: "qualified", position: -1, length: length}
+}
+
+// A nested struct with the fields of 'layout'.
+func Nested(layout *LayoutBuilder) FieldSpec {
+	return FieldSpec{typeName: "struct", position: -1, length: -1, nested: layout}
+}
+
+// An array ending with the terminator. ('array:terminator')
+func Terminated() ArraySize {
+	return ArraySize{annotation: "array:terminator"}
+}
+
+// An array with 'size' elements. ('array:<size>')
+func Fixed(size int) ArraySize {
+	return ArraySize{annotation: "array:" + strconv.Itoa(size)}
+}
+
+// An array with the number of elements in the int field 'fieldName' read before. ('array:<fieldName>')
+func SizedBy(fieldName string) ArraySize {
+	return ArraySize{annotation: "array:" + fieldName}
+}
+
+// Places the field at the absolute 'position' within its struct. ('<position>:<length>')
+// Not for nested structs and arrays of them - Schema and RegisterLayout return an ErrorUnsupportedPosition.
+func (f FieldSpec) At(position int) FieldSpec {
+	f.position = position
+	return f
+}
+
+// Removes the surrounding spaces when unmarshaling. ('trim')
+func (f FieldSpec) Trim() FieldSpec {
+	return f.with("trim")
+}
+
+// Removes the leading spaces when unmarshaling. ('ltrim')
+func (f FieldSpec) LTrim() FieldSpec {
+	return f.with("ltrim")
+}
+
+// Removes the trailing spaces when unmarshaling. ('rtrim')
+func (f FieldSpec) RTrim() FieldSpec {
+	return f.with("rtrim")
+}
+
+// Pads numbers with spaces instead of zeros. ('padspace')
+func (f FieldSpec) Padspace() FieldSpec {
+	return f.with("padspace")
+}
+
+// Writes a '+' for positive numbers. ('forcesign')
+func (f FieldSpec) ForceSign() FieldSpec {
+	return f.with("forcesign")
+}
+
+// Places the sign of numbers, the default is SignLeading. ('sign:leading|trailing|space|none')
+func (f FieldSpec) Sign(sign Sign) FieldSpec {
+	return f.with("sign:" + string(sign))
+}
+
+// Writes floats with 'decimalPlaces' digits after the point. ('precision:<decimalPlaces>')
+func (f FieldSpec) Precision(decimalPlaces int) FieldSpec {
+	return f.with("precision:" + strconv.Itoa(decimalPlaces))
+}
+
+// Aligns strings within the field, the default is AlignRight. ('align:left|right|center')
+func (f FieldSpec) Align(alignment Alignment) FieldSpec {
+	return f.with("align:" + string(alignment))
+}
+
+// Pads strings with 'char' instead of spaces. ('fill:<char>')
+func (f FieldSpec) Fill(char byte) FieldSpec {
+	return f.with("fill:" + quoteAnnotationValue(string([]byte{char})))
+}
+
+// Cuts strings that are too long instead of failing. ('truncate')
+func (f FieldSpec) Truncate() FieldSpec {
+	return f.with("truncate")
+}
+
+// Writes floats in the 'notation', the default is NotationFixed for float32 and NotationScientific for float64. ('notation:fixed|sci|auto')
+func (f FieldSpec) Notation(notation Notation) FieldSpec {
+	return f.with("notation:" + string(notation))
+}
+
+// Writes the exponent of floats in scientific notation with 'digits' digits at least, 1 to 3. ('expdigits:<digits>')
+func (f FieldSpec) ExpDigits(digits int) FieldSpec {
+	return f.with("expdigits:" + strconv.Itoa(digits))
+}
+
+// Writes integral floats without a trailing point. With a Precision above 0, the decimals are implied: 6.40 is "640" for 2. ('nopoint')
+func (f FieldSpec) NoPoint() FieldSpec {
+	return f.with("nopoint")
+}
+
+// Uses 'separator' as decimal separator of floats, ex.: ','. ('decimal:<char>')
+func (f FieldSpec) Decimal(separator byte) FieldSpec {
+	return f.with("decimal:" + quoteAnnotationValue(string([]byte{separator})))
+}
+
+// Allows 'separator' as thousands separator in floats when unmarshaling, ex.: '.'. ('thousands:<char>')
+func (f FieldSpec) Thousands(separator byte) FieldSpec {
+	return f.with("thousands:" + quoteAnnotationValue(string([]byte{separator})))
+}
+
+// Rounds floats to their precision with the 'rounding' mode instead of the rounding of strconv.FormatFloat. ('rounding:half-up|half-even|truncate')
+func (f FieldSpec) Rounding(rounding Rounding) FieldSpec {
+	return f.with("rounding:" + string(rounding))
+}
+
+// Handles numbers that don't fit into the field with the 'overflow' policy, the default is OverflowError. ('overflow:error|clamp|fill')
+func (f FieldSpec) Overflow(overflow Overflow) FieldSpec {
+	return f.with("overflow:" + string(overflow))
+}
+
+// Uses 'literal' for a blank field when unmarshaling. ('default:<literal>')
+func (f FieldSpec) Default(literal string) FieldSpec {
+	return f.with("default:" + quoteAnnotationValue(literal))
+}
+
+// Writes the field blank for its zero value when marshaling. ('blankdefault')
+func (f FieldSpec) BlankDefault() FieldSpec {
+	return f.with("blankdefault")
+}
+
+// Reads the field as absent, if it is blank or contains one of the 'literals'. A nil pointer or an invalid Null (see Pointer and Null) writes the first literal. ('placeholder[:<literal>|...]')
+func (f FieldSpec) Placeholder(literals ...string) FieldSpec {
+	if len(literals) == 0 {
+		return f.with("placeholder")
+	}
+	return f.with("placeholder:" + quoteAnnotationValue(strings.Join(literals, "|")))
+}
+
+// Stores unknown codes in the string field 'fieldName' instead of failing. ('fallback:<fieldName>')
+func (f FieldSpec) Fallback(fieldName string) FieldSpec {
+	return f.with("fallback:" + fieldName)
+}
+
+// Only processes the field if the field 'fieldName' read before holds 'value'. ('if:<fieldName>=<value>')
+func (f FieldSpec) If(fieldName string, value string) FieldSpec {
+	return f.with("if:" + quoteAnnotationValue(fieldName+"="+value))
+}
+
+// Fails validation if the field is absent: a blank string, a nil pointer, an invalid Null or a placeholder. ('required')
+func (f FieldSpec) Required() FieldSpec {
+	return f.with("required")
+}
+
+// Fails validation if the number or the length of the string is lower than 'limit'. ('min:<limit>')
+func (f FieldSpec) Min(limit float64) FieldSpec {
+	return f.with("min:" + strconv.FormatFloat(limit, 'f', -1, 64))
+}
+
+// Fails validation if the number or the length of the string is greater than 'limit'. ('max:<limit>')
+func (f FieldSpec) Max(limit float64) FieldSpec {
+	return f.with("max:" + strconv.FormatFloat(limit, 'f', -1, 64))
+}
+
+// Fails validation if the value doesn't match the regular expression 'expr'. ('pattern:<expr>')
+func (f FieldSpec) Pattern(expr string) FieldSpec {
+	return f.with("pattern:" + quoteAnnotationValue(expr))
+}
+
+// Fails validation if the value is none of 'values'. ('oneof:<value>|<value>')
+func (f FieldSpec) OneOf(values ...string) FieldSpec {
+	return f.with("oneof:" + quoteAnnotationValue(strings.Join(values, "|")))
+}
+
+func (f FieldSpec) elementSpec() FieldSpec {
+	return f
+}
+
+// Makes the field a pointer, nil for absent values, ex.: *int. Not for nested structs.
+func (f FieldSpec) Pointer() FieldSpec {
+	f.typeName = "*" + f.typeName
+	return f
+}
+
+// Makes the field a Null, invalid for absent values, ex.: Null[int]. Not for nested structs.
+func (f FieldSpec) Null() FieldSpec {
+	f.typeName = "null[" + f.typeName + "]"
+	return f
+}
+
+// Returns a copy with the 'annotation' added, so specs can be shared between fields.
+func (f FieldSpec) with(annotation string) FieldSpec {
+	f.annotations = append(append([]string{}, f.annotations...), annotation)
+	return f
+}
+
+// Returns the field as SchemaField. For arrays, the type is prefixed with "[]" and 'arrayAnnotation' comes first.
+func (f FieldSpec) schemaField(name string, typePrefix string, arrayAnnotation string) SchemaField {
+
+	var annotations []string
+	if arrayAnnotation != "" {
+		annotations = append(annotations, arrayAnnotation)
+	}
+	if f.length >= 0 {
+		var address = ":" + strconv.Itoa(f.length)
+		if f.position >= 0 {
+			address = strconv.Itoa(f.position) + address
+		}
+		annotations = append(annotations, address)
+	}
+	annotations = append(annotations, f.annotations...)
+
+	var field = SchemaField{Name: name, Type: typePrefix + f.typeName, Bin: strings.Join(annotations, ",")}
+	if f.nested != nil {
+		field.Fields = f.nested.fields
+	}
+	return field
+}
+
+// Registers the 'layout' for the struct type 'T'. Its fields are then processed with the annotations of the
+// layout instead of their 'bin' tags - fields not in the layout are processed like fields without a tag. The layouts of nested structs and
+// arrays of them are registered for their types as well.
+//
+// Registering a layout for the same type again replaces the previous one.
+// Returns an ErrorInvalidSchema if a field of the layout is missing in 'T', has another type or is out of order.
+func RegisterLayout[T any](layout *LayoutBuilder) error {
+
+	if layout.err != nil {
+		return layout.err
+	}
+
+	var bindings = map[reflect.Type][]string{}
+	if err := bindLayout(reflect.TypeOf((*T)(nil)).Elem(), layout.fields, "", bindings); err != nil {
+		return err
+	}
+
+	for structType, annotations := range bindings {
+		structAnnotations.Store(structType, parseFieldAnnotations(annotations))
+	}
+
+	return nil
+}
+
+// Collects the annotations of the layout 'fields' for each field of 'structType' in 'bindings', nested structs included.
+// 'path' is the path of the parent field for error messages.
+func bindLayout(structType reflect.Type, fields []SchemaField, path string, bindings map[reflect.Type][]string) error {
+
+	if structType.Kind() != reflect.Struct {
+		return newInvalidSchemaError(path, newUnsupportedTypeError(structType))
+	}
+
+	var annotations = make([]string, structType.NumField())
+	var lastIndex = -1
+
+	for _, field := range fields {
+
+		var fieldPath = joinFieldPath(path, field.Name)
+
+		var structField, isFound = structType.FieldByName(field.Name)
+		if !isFound || len(structField.Index) != 1 {
+			return newInvalidSchemaError(fieldPath, ErrorUnknownFieldName)
+		}
+		if structField.Index[0] <= lastIndex {
+			return newInvalidSchemaError(fieldPath, ErrorFieldOrderMismatch) // the fields are processed in the order of the struct
+		}
+		lastIndex = structField.Index[0]
+
+		var layoutType, err = buildSchemaFieldType(field, fieldPath)
+		if err != nil {
+			return err
+		}
+
+		var fieldType = structField.Type
+		if layoutType.Kind() == reflect.Slice {
+			if fieldType.Kind() != reflect.Slice {
+				return newInvalidSchemaError(fieldPath, ErrorFieldTypeMismatch)
+			}
+			layoutType, fieldType = layoutType.Elem(), fieldType.Elem()
+		}
+		if !isLayoutTypeMatch(layoutType, fieldType) {
+			return newInvalidSchemaError(fieldPath, ErrorFieldTypeMismatch)
+		}
+		if fieldType.Kind() == reflect.Struct && !isValueStructType(fieldType) {
+			if err = bindLayout(fieldType, field.Fields, fieldPath, bindings); err != nil {
+				return err
+			}
+		}
+
+		annotations[structField.Index[0]] = field.Bin
+	}
+
+	bindings[structType] = annotations
+
+	return nil
+}
+
+// Returns true if a struct field of 'fieldType' can be processed as the type 'layoutType' of a layout field.
+// Primitive types only need the same kind, so named types match as well - ex.: the types of code tables.
+func isLayoutTypeMatch(layoutType reflect.Type, fieldType reflect.Type) bool {
+	switch {
+	case isNullType(layoutType):
+		return isNullType(fieldType) && isLayoutTypeMatch(layoutType.Field(0).Type, fieldType.Field(0).Type) // the Values
+	case isValueStructType(layoutType):
+		return layoutType == fieldType
+	case layoutType.Kind() == reflect.Ptr:
+		return fieldType.Kind() == reflect.Ptr && isLayoutTypeMatch(layoutType.Elem(), fieldType.Elem())
+	}
+	return layoutType.Kind() == fieldType.Kind() && !isValueStructType(fieldType)
+}
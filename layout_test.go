@@ -0,0 +1,260 @@
+package binfile
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//
+//-Layout Builder--------------------------------------------------------------
+
+type testLayoutResult struct {
+	TestCode   string
+	TestResult float32
+}
+
+type testLayoutMessage struct {
+	RecordType  string
+	SampleId    string
+	internal    string
+	Count       int
+	Flags       []int
+	TestResults []testLayoutResult
+}
+
+func testMessageLayout() *LayoutBuilder {
+
+	var result = Layout().
+		Field("TestCode", String(2)).
+		Field("TestResult", Float32(6).Padspace().Precision(2))
+
+	return Layout().
+		Field("RecordType", String(1)).
+		Field("SampleId", String(6).At(3).Trim().Required()).
+		Field("Count", Int(1)).
+		Array("Flags", SizedBy("Count"), Int(1)).
+		Array("TestResults", Terminated(), result)
+}
+
+func TestLayoutBuilderSchema(t *testing.T) {
+
+	schema, err := testMessageLayout().Schema()
+	assert.Nil(t, err)
+
+	assert.Equal(t, []SchemaField{
+		{Name: "RecordType", Type: "string", Bin: ":1"},
+		{Name: "SampleId", Type: "string", Bin: "3:6,trim,required"},
+		{Name: "Count", Type: "int", Bin: ":1"},
+		{Name: "Flags", Type: "[]int", Bin: "array:Count,:1"},
+		{Name: "TestResults", Type: "[]struct", Bin: "array:terminator", Fields: []SchemaField{
+			{Name: "TestCode", Type: "string", Bin: ":2"},
+			{Name: "TestResult", Type: "float32", Bin: ":6,padspace,precision:2"},
+		}},
+	}, schema.Fields)
+
+	result, _, err := schema.Unmarshal([]byte("Dxx  S12321261  6.40\r"), EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"RecordType":  "D",
+		"SampleId":    "S123",
+		"Count":       2,
+		"Flags":       []interface{}{1, 2},
+		"TestResults": []interface{}{map[string]interface{}{"TestCode": "61", "TestResult": float32(6.4)}},
+	}, result)
+
+	//-------------------------------------------------------------------------
+
+	// shared specs are not changed by adding annotations
+	var code = String(2)
+	var trimmedCode = code.Trim()
+	schema, err = Layout().Field("A", code).Field("B", trimmedCode).Struct("C", Layout().Array("D", Fixed(1), code)).Schema()
+	assert.Nil(t, err)
+	assert.Equal(t, ":2", schema.Fields[0].Bin)
+	assert.Equal(t, ":2,trim", schema.Fields[1].Bin)
+	assert.Equal(t, "array:1,:2", schema.Fields[2].Fields[0].Bin)
+
+	// values are quoted where needed
+	schema, err = Layout().Field("A", String(4).Default("a, b").Pattern(`^\w, \w$`)).Field("B", Int(2).Default("0")).Schema()
+	assert.Nil(t, err)
+	assert.Equal(t, `:4,default:'a, b',pattern:'^\\w, \\w$'`, schema.Fields[0].Bin)
+	assert.Equal(t, ":2,default:0", schema.Fields[1].Bin)
+
+	result, _, err = schema.Unmarshal([]byte("    12"), EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"A": "a, b", "B": 12}, result)
+}
+
+func TestLayoutBuilderPlaceholder(t *testing.T) {
+
+	schema, err := Layout().Field("A", Int(3).Placeholder()).Field("B", Int(5).Placeholder("***", "N/A")).Schema()
+	assert.Nil(t, err)
+	assert.Equal(t, ":3,placeholder", schema.Fields[0].Bin)
+	assert.Equal(t, ":5,placeholder:***|N/A", schema.Fields[1].Bin)
+}
+
+func TestLayoutBuilderRegister(t *testing.T) {
+
+	var err = RegisterLayout[testLayoutMessage](testMessageLayout())
+	assert.Nil(t, err)
+
+	var data = "Dxx  S12321261  6.4062  1.50\r"
+
+	var result testLayoutMessage
+	position, err := Unmarshal([]byte(data), &result, EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(data), position)
+	assert.Equal(t, testLayoutMessage{
+		RecordType: "D",
+		SampleId:   "S123",
+		Count:      2,
+		Flags:      []int{1, 2},
+		TestResults: []testLayoutResult{
+			{TestCode: "61", TestResult: 6.4},
+			{TestCode: "62", TestResult: 1.5},
+		},
+	}, result)
+
+	output, err := Marshal(result, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, "D    S12321261  6.4062  1.50\r", string(output))
+
+	// the validation annotations are registered as well
+	_, err = Unmarshal([]byte("Dxx        0\r"), &testLayoutMessage{}, EncodingUTF8, TimezoneUTC, "\r")
+	var errValidation *ErrorValidation
+	assert.Equal(t, true, errors.As(err, &errValidation))
+
+	//-------------------------------------------------------------------------
+
+	var errInvalidSchema *ErrorInvalidSchema
+
+	err = RegisterLayout[testLayoutMessage](Layout().Field("SampleID", String(6)))
+	assert.Equal(t, true, errors.As(err, &errInvalidSchema))
+	assert.Equal(t, "SampleID", errInvalidSchema.FieldPath)
+	assert.Equal(t, true, errors.Is(err, ErrorUnknownFieldName))
+
+	err = RegisterLayout[testLayoutMessage](Layout().Field("Count", String(1)))
+	assert.Equal(t, true, errors.Is(err, ErrorFieldTypeMismatch))
+
+	err = RegisterLayout[testLayoutMessage](Layout().Field("SampleId", String(6)).Field("RecordType", String(1)))
+	assert.Equal(t, true, errors.Is(err, ErrorFieldOrderMismatch))
+
+	err = RegisterLayout[testLayoutMessage](Layout().Array("TestResults", Terminated(), Layout().Field("Unknown", Int(1))))
+	assert.Equal(t, true, errors.As(err, &errInvalidSchema))
+	assert.Equal(t, "TestResults.Unknown", errInvalidSchema.FieldPath)
+
+	//-------------------------------------------------------------------------
+
+	// an absolute position needs a length
+	var resultLayout = Layout().Field("TestCode", String(2))
+
+	err = RegisterLayout[testLayoutMessage](Layout().Array("TestResults", Terminated(), Nested(resultLayout).At(4)))
+	assert.Equal(t, true, errors.As(err, &errInvalidSchema))
+	assert.Equal(t, "TestResults", errInvalidSchema.FieldPath)
+	assert.Equal(t, true, errors.Is(err, ErrorUnsupportedPosition))
+
+	_, err = Layout().Struct("Block", Layout().Field("Inner", Nested(resultLayout).At(2))).Schema()
+	assert.Equal(t, true, errors.As(err, &errInvalidSchema))
+	assert.Equal(t, "Block.Inner", errInvalidSchema.FieldPath)
+	assert.Equal(t, true, errors.Is(err, ErrorUnsupportedPosition))
+}
+
+type testLayoutReplaced struct {
+	Code  string `bin:":2"`
+	Value int    `bin:":2"`
+}
+
+func TestLayoutBuilderReplacesTags(t *testing.T) {
+
+	var result testLayoutReplaced
+	_, err := Unmarshal([]byte("A012"), &result, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, testLayoutReplaced{Code: "A0", Value: 12}, result)
+
+	// registered after the tags were used
+	err = RegisterLayout[testLayoutReplaced](Layout().Field("Code", String(1)).Field("Value", Int(3)))
+	assert.Nil(t, err)
+
+	_, err = Unmarshal([]byte("A012"), &result, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, testLayoutReplaced{Code: "A", Value: 12}, result)
+}
+
+//
+//-Layout Builder Value Types--------------------------------------------------
+
+type testLayoutValueTypes struct {
+	Count    *int
+	Factor   Null[float32]
+	Amount   Decimal
+	Result   QualifiedNumber
+	Optional Null[Decimal]
+	Flags    []*int
+}
+
+func testValueTypesLayout() *LayoutBuilder {
+	return Layout().
+		Field("Count", Int(2).Pointer()).
+		Field("Factor", Float32(4).Null().Placeholder("****")).
+		Field("Amount", DecimalField(6)).
+		Field("Result", QualifiedField(5)).
+		Field("Optional", DecimalField(4).Null()).
+		Array("Flags", Fixed(2), Int(1).Pointer())
+}
+
+func TestLayoutBuilderValueTypes(t *testing.T) {
+
+	schema, err := testValueTypesLayout().Schema()
+	assert.Nil(t, err)
+	assert.Equal(t, []SchemaField{
+		{Name: "Count", Type: "*int", Bin: ":2"},
+		{Name: "Factor", Type: "null[float32]", Bin: ":4,placeholder:****"},
+		{Name: "Amount", Type: "decimal", Bin: ":6"},
+		{Name: "Result", Type: "qualified", Bin: ":5"},
+		{Name: "Optional", Type: "null[decimal]", Bin: ":4"},
+		{Name: "Flags", Type: "[]*int", Bin: "array:2,:1"},
+	}, schema.Fields)
+
+	err = RegisterLayout[testLayoutValueTypes](testValueTypesLayout())
+	assert.Nil(t, err)
+
+	var data = "  ****006.40<00.5    1 "
+	var result testLayoutValueTypes
+	_, err = Unmarshal([]byte(data), &result, EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Nil(t, result.Count)
+	assert.Equal(t, Null[float32]{}, result.Factor)
+	assert.Equal(t, "6.40", result.Amount.String())
+	assert.Equal(t, QualifiedNumber{Qualifier: "<", Value: 0.5, Raw: "<00.5"}, result.Result)
+	assert.Equal(t, false, result.Optional.Valid)
+	assert.Equal(t, 1, *result.Flags[0])
+	assert.Nil(t, result.Flags[1])
+
+	output, err := Marshal(result, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, data, string(output))
+
+	//-------------------------------------------------------------------------
+
+	err = RegisterLayout[testLayoutValueTypes](Layout().Field("Count", Int(2)))
+	assert.Equal(t, true, errors.Is(err, ErrorFieldTypeMismatch))
+
+	err = RegisterLayout[testLayoutValueTypes](Layout().Field("Factor", Float32(4).Pointer()))
+	assert.Equal(t, true, errors.Is(err, ErrorFieldTypeMismatch))
+
+	err = RegisterLayout[testLayoutValueTypes](Layout().Field("Factor", Float64(4).Null()))
+	assert.Equal(t, true, errors.Is(err, ErrorFieldTypeMismatch))
+
+	err = RegisterLayout[testLayoutValueTypes](Layout().Field("Amount", Float64(6)))
+	assert.Equal(t, true, errors.Is(err, ErrorFieldTypeMismatch))
+
+	err = RegisterLayout[testLayoutValueTypes](Layout().Field("Result", DecimalField(5)))
+	assert.Equal(t, true, errors.Is(err, ErrorFieldTypeMismatch))
+
+	_, err = Layout().Field("Block", Nested(Layout().Field("A", Int(1))).Pointer()).Schema()
+	assert.Equal(t, true, errors.Is(err, ErrorUnknownFieldType))
+}
@@ -1,8 +1,12 @@
 package binfile
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"reflect"
 	"strconv"
+	"strings"
 )
 
 // Accepts an annotated struct or slice of structs.
@@ -10,13 +14,16 @@ import (
 // Returns a byte array with the converted contents or an error.
 //
 // Check the README.md for usage.
-func Marshal(target interface{}, padding byte, enc Encoding, tz Timezone, arrayTerminator string) ([]byte, error) {
+func Marshal(target interface{}, padding byte, enc Encoding, tz Timezone, arrayTerminator string, options ...Option) ([]byte, error) {
 
 	// TODO: accepting a Ptr here is confusing as the func will not change the contents
 	if reflect.TypeOf(target).Kind() == reflect.Ptr {
-		return Marshal(reflect.ValueOf(target).Elem(), padding, enc, tz, arrayTerminator)
+		return Marshal(reflect.ValueOf(target).Elem().Interface(), padding, enc, tz, arrayTerminator, options...)
 	}
 
+	var opts = newOptions(options)
+	opts.lenient = false // only decoding can be lenient
+	var collectedErrors []error
 	var outBytes []byte
 	var err error
 	var depth = 0
@@ -36,8 +43,9 @@ func Marshal(target interface{}, padding byte, enc Encoding, tz Timezone, arrayT
 				// TODO: slice of slices?
 
 			case reflect.Struct:
-				tempBytes, _, err = internalMarshal(targetValue.Index(i), false, padding, arrayTerminator, 0, depth+1)
-				if err != nil {
+				tempBytes, _, err = internalMarshal(targetValue.Index(i), false, padding, arrayTerminator, 0, depth+1, opts)
+				err = withRecordPosition(err, i, len(outBytes))
+				if collectedErrors, err = opts.collectError(collectedErrors, err); err != nil {
 					return []byte{}, err
 				}
 				outBytes = append(outBytes, tempBytes...)
@@ -50,10 +58,13 @@ func Marshal(target interface{}, padding byte, enc Encoding, tz Timezone, arrayT
 			outBytes = append(outBytes, []byte(arrayTerminator)...)
 		}
 
+		if len(collectedErrors) > 0 {
+			return outBytes, newErrorCollection(collectedErrors)
+		}
 		return outBytes, err
 
 	case reflect.Struct:
-		outBytes, _, err = internalMarshal(targetValue, false, padding, arrayTerminator, 0, depth)
+		outBytes, _, err = internalMarshal(targetValue, false, padding, arrayTerminator, 0, depth, opts)
 		return outBytes, err
 
 	}
@@ -62,37 +73,54 @@ func Marshal(target interface{}, padding byte, enc Encoding, tz Timezone, arrayT
 }
 
 // use this for recursion
-func internalMarshal(record reflect.Value, onlyPaddWithZeros bool, padding byte, arrayTerminator string, currentByte int, depth int) ([]byte, int, error) {
+func internalMarshal(record reflect.Value, onlyPaddWithZeros bool, padding byte, arrayTerminator string, currentByte int, depth int, opts *options) ([]byte, int, error) {
 
 	outBytes := []byte{}
+	var collectedErrors []error
 
 	for fieldNo := 0; fieldNo < record.NumField(); fieldNo++ {
 
 		var recordField = record.Field(fieldNo)
 
-		var binTag = record.Type().Field(fieldNo).Tag.Get("bin")
+		var binTag, annotationList, hasAnnotations, err = getFieldAnnotations(record.Type(), fieldNo)
 		if !recordField.CanInterface() {
 			if binTag != "" {
-				return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, ErrorExportedFieldNotAnnotated)
+				return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, currentByte, ErrorExportedFieldNotAnnotated)
 			} else {
 				continue // TODO: this won't notify you about accidentally not exported nested structs
 			}
 		}
 
-		var annotationList, hasAnnotations = getAnnotationList(binTag)
+		if err != nil {
+			return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, currentByte, err)
+		}
+
+		if conditionField, conditionValue, hasCondition, err := getConditionAnnotation(annotationList); err != nil {
+			return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, currentByte, err)
+		} else if hasCondition {
+			isConditionMet, err := evaluateCondition(record, conditionField, conditionValue)
+			if err != nil {
+				return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, currentByte, newInvalidConditionError(conditionField, err))
+			}
+			if !isConditionMet {
+				continue // The field is not present in this record
+			}
+		}
 
 		absoluteAnnotatedPos, relativeAnnotatedLength, hasAnnotatedAddress, err := getAddressAnnotation(annotationList)
 		if err != nil {
-			return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, newInvalidAddressAnnotationError(err))
+			return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, currentByte, newInvalidAddressAnnotationError(err))
 		}
 
 		if absoluteAnnotatedPos != -1 {
 			if currentByte < absoluteAnnotatedPos {
 				outBytes, currentByte = appendPaddingBytes(outBytes, absoluteAnnotatedPos-currentByte, padding)
 			} else if currentByte > absoluteAnnotatedPos {
-				return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, newInvalidInvalidOffsetError(currentByte, absoluteAnnotatedPos))
+				return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, currentByte, newInvalidInvalidOffsetError(currentByte, absoluteAnnotatedPos))
 			}
 		}
+
+		var fieldStartByte = currentByte
 		/*
 			for k := 0; k < depth; k++ {
 				fmt.Print(" ")
@@ -102,13 +130,15 @@ func internalMarshal(record reflect.Value, onlyPaddWithZeros bool, padding byte,
 				absoluteAnnotatedPos, relativeAnnotatedLength, currentByte)*/
 
 		var valueKind = reflect.TypeOf(recordField.Interface()).Kind()
-		if valueKind == reflect.Struct {
+		if valueKind == reflect.Struct && !isValueStructType(recordField.Type()) {
 
 			var tempOutByte []byte
 			var err error
-			tempOutByte, currentByte, err = internalMarshal(recordField, onlyPaddWithZeros, padding, arrayTerminator, currentByte, depth+1)
+			tempOutByte, currentByte, err = internalMarshal(recordField, onlyPaddWithZeros, padding, arrayTerminator, currentByte, depth+1, opts)
 			if err != nil { // If the nested structure did fail, then bail out
-				return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, err)
+				if collectedErrors, err = opts.collectError(collectedErrors, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, fieldStartByte, err)); err != nil {
+					return []byte{}, currentByte, err
+				}
 			}
 
 			outBytes = append(outBytes, tempOutByte...)
@@ -124,14 +154,17 @@ func internalMarshal(record reflect.Value, onlyPaddWithZeros bool, padding byte,
 
 			var arrayAnnotation, hasArrayAnnotation = getArrayAnnotation(annotationList)
 			if !hasArrayAnnotation {
-				return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, ErrorMissingArrayAnnotation)
+				return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, fieldStartByte, ErrorMissingArrayAnnotation)
 			}
 
 			var sliceValue = reflect.ValueOf(recordField.Interface())
 			var innerValueKind = reflect.TypeOf(recordField.Interface()).Elem().Kind()
+			if isValueStructType(recordField.Type().Elem()) {
+				innerValueKind = reflect.Invalid // a single value, not a nested struct
+			}
 
 			if innerValueKind != reflect.Struct && !hasAnnotatedAddress {
-				return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, ErrorMissingAddressAnnotation)
+				return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, fieldStartByte, ErrorMissingAddressAnnotation)
 			}
 
 			var arraySize = sliceValue.Len()
@@ -142,7 +175,7 @@ func internalMarshal(record reflect.Value, onlyPaddWithZeros bool, padding byte,
 				} else if fieldName, isDynamic := getArraySizeFieldName(arrayAnnotation); isDynamic {
 					arraySize, err = resolveDynamicArraySize(record, fieldName)
 					if err != nil {
-						return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, newInvalidDynamicArraySizeError(record.Type().Name(), fieldName, err))
+						return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, fieldStartByte, newInvalidDynamicArraySizeError(record.Type().Name(), fieldName, err))
 					}
 				}
 			}
@@ -151,6 +184,8 @@ func internalMarshal(record reflect.Value, onlyPaddWithZeros bool, padding byte,
 			var err error
 			for i := 0; i < arraySize; i++ {
 
+				var elementStartByte = currentByte
+
 				var currentElement reflect.Value
 				if i < sliceValue.Len() {
 					currentElement = sliceValue.Index(i)
@@ -162,17 +197,27 @@ func internalMarshal(record reflect.Value, onlyPaddWithZeros bool, padding byte,
 				switch innerValueKind {
 				case reflect.Struct:
 
-					tempOutByte, currentByte, err = internalMarshal(currentElement, onlyPaddWithZeros, padding, arrayTerminator, currentByte, depth+1)
+					tempOutByte, currentByte, err = internalMarshal(currentElement, onlyPaddWithZeros, padding, arrayTerminator, currentByte, depth+1, opts)
 					if err != nil {
-						return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, err)
+						if collectedErrors, err = opts.collectError(collectedErrors, newProcessingFieldError(indexedFieldName(record.Type().Field(fieldNo).Name, i), binTag, elementStartByte, err)); err != nil {
+							return []byte{}, currentByte, err
+						}
 					}
 					outBytes = append(outBytes, tempOutByte...)
 
 				default:
 
-					tempOutByte, currentByte, err = marshalSimpleTypes(currentElement, onlyPaddWithZeros, relativeAnnotatedLength, annotationList, currentByte, depth)
+					if !onlyPaddWithZeros {
+						if err = validateField(currentElement, false, annotationList); err != nil {
+							if collectedErrors, err = opts.collectError(collectedErrors, newProcessingFieldError(indexedFieldName(record.Type().Field(fieldNo).Name, i), binTag, elementStartByte, err)); err != nil {
+								return []byte{}, currentByte, err
+							}
+						}
+					}
+
+					tempOutByte, currentByte, err = marshalSimpleTypes(currentElement, onlyPaddWithZeros, relativeAnnotatedLength, annotationList, currentByte, depth, opts)
 					if err != nil {
-						return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, err)
+						return []byte{}, currentByte, newProcessingFieldError(indexedFieldName(record.Type().Field(fieldNo).Name, i), binTag, elementStartByte, err)
 					}
 					outBytes = append(outBytes, tempOutByte...)
 				}
@@ -190,23 +235,46 @@ func internalMarshal(record reflect.Value, onlyPaddWithZeros bool, padding byte,
 		}
 
 		if !hasAnnotatedAddress {
-			return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, ErrorMissingAddressAnnotation)
+			return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, fieldStartByte, ErrorMissingAddressAnnotation)
+		}
+
+		if !onlyPaddWithZeros {
+			if err = validateField(recordField, false, annotationList); err != nil {
+				if collectedErrors, err = opts.collectError(collectedErrors, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, fieldStartByte, err)); err != nil {
+					return []byte{}, currentByte, err
+				}
+			}
 		}
 
 		var tempOutByte []byte
-		tempOutByte, currentByte, err = marshalSimpleTypes(recordField, onlyPaddWithZeros, relativeAnnotatedLength, annotationList, currentByte, depth)
+		tempOutByte, currentByte, err = marshalSimpleTypes(recordField, onlyPaddWithZeros, relativeAnnotatedLength, annotationList, currentByte, depth, opts)
+		var errUnknownCode *ErrorUnknownCode
+		if fallbackName, hasFallback := getFallbackAnnotation(annotationList); hasFallback && errors.As(err, &errUnknownCode) {
+			var fallbackCode string
+			if fallbackCode, err = getFallbackCode(record, fallbackName); err != nil {
+				err = newInvalidFallbackError(fallbackName, err)
+			} else if fallbackCode == "" {
+				err = errUnknownCode // nothing to fall back to
+			} else {
+				tempOutByte, currentByte, err = marshalSimpleTypes(reflect.ValueOf(fallbackCode), onlyPaddWithZeros, relativeAnnotatedLength, annotationList, currentByte, depth, opts)
+			}
+		}
 		if err != nil {
-			return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, err)
+			return []byte{}, currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, fieldStartByte, err)
 		}
 		outBytes = append(outBytes, tempOutByte...)
 
 	}
 
+	if len(collectedErrors) > 0 {
+		return outBytes, currentByte, newErrorCollection(collectedErrors)
+	}
+
 	return outBytes, currentByte, nil
 }
 
 // use this for processing end nodes
-func marshalSimpleTypes(recordField reflect.Value, onlyPaddWithZeros bool, relativeAnnotatedLength int, annotationList []string, currentByte int, depth int) ([]byte, int, error) {
+func marshalSimpleTypes(recordField reflect.Value, onlyPaddWithZeros bool, relativeAnnotatedLength int, annotationList []string, currentByte int, depth int, opts *options) ([]byte, int, error) {
 
 	if onlyPaddWithZeros {
 		return make([]byte, relativeAnnotatedLength), currentByte + relativeAnnotatedLength, nil
@@ -214,18 +282,125 @@ func marshalSimpleTypes(recordField reflect.Value, onlyPaddWithZeros bool, relat
 
 	var outBytes = []byte{}
 
+	var isNull = isNullType(recordField.Type())
+	if isNull {
+		if !recordField.FieldByName("Valid").Bool() {
+			recordField = reflect.Zero(reflect.PtrTo(recordField.Type())) // written like a nil pointer
+		} else {
+			recordField = recordField.FieldByName("Value")
+		}
+	}
+
+	if recordField.Kind() == reflect.Ptr {
+		if recordField.IsNil() { // no value: the first placeholder or blank
+			var placeholders, _ = getPlaceholderAnnotation(annotationList)
+			var literal = []byte{}
+			if len(placeholders) > 0 {
+				literal = []byte(placeholders[0])
+			}
+			if len(literal) > relativeAnnotatedLength {
+				return []byte{}, currentByte, newInvalidValueLengthError(string(literal), len(literal))
+			}
+			var alignment, _ = getAlignAnnotation(annotationList)
+			return alignBytes(literal, relativeAnnotatedLength, alignment, ' '), currentByte + relativeAnnotatedLength, nil
+		}
+		recordField = recordField.Elem()
+	}
+
+	if defaultLiteral, hasDefault := getDefaultAnnotation(annotationList); hasDefault && hasAnnotationBlankDefault(annotationList) {
+		var defaultValue = reflect.New(recordField.Type()).Elem()
+		if err := setSimpleValue(defaultValue, defaultLiteral, annotationList, opts); err != nil {
+			return []byte{}, currentByte, newInvalidDefaultError(defaultLiteral, err)
+		}
+		var isDefault = reflect.DeepEqual(defaultValue.Interface(), recordField.Interface())
+		if number, isDecimal := recordField.Interface().(Decimal); isDecimal {
+			isDefault = number.Cmp(defaultValue.Interface().(Decimal)) == 0 // regardless of the scale
+		}
+		if isDefault {
+			outBytes, _ = appendPaddingBytes(outBytes, relativeAnnotatedLength, byte(' '))
+			return outBytes, currentByte + relativeAnnotatedLength, nil
+		}
+	}
+
+	if table, hasCodeTable := getCodeTable(recordField.Type()); hasCodeTable {
+		literal, err := table.encode(recordField.Interface())
+		if err != nil {
+			return []byte{}, currentByte, err
+		}
+		return marshalSimpleTypes(reflect.ValueOf(literal), onlyPaddWithZeros, relativeAnnotatedLength, annotationList, currentByte, depth, opts)
+	}
+
+	if number, isDecimal := recordField.Interface().(Decimal); isDecimal {
+
+		var precision, err = getPrecisionFromAnnotation(annotationList)
+		if err != nil {
+			return []byte{}, currentByte, err
+		}
+		if precision >= 0 {
+			var rounding, hasRounding = getRoundingAnnotation(annotationList)
+			if !hasRounding {
+				rounding = "half-even" // like floats are rounded
+			}
+			number = number.rescale(precision, rounding)
+		}
+
+		var isNegative = number.Sign() < 0
+		sign, err := getNumberSign(number, isNegative, annotationList)
+		if err != nil {
+			return []byte{}, currentByte, err
+		}
+
+		var tempStr = number.String()
+		if isNegative { // handle negative sign separately
+			tempStr = tempStr[1:]
+		}
+		var scale = number.Scale()
+		if _, isImplied := getImpliedDecimals(annotationList); isImplied {
+			tempStr, scale = strings.Replace(tempStr, ".", "", 1), 0 // ex.: "640" for 6.40
+		}
+		var separator = getDecimalSeparator(annotationList, opts)
+		if separator != '.' {
+			tempStr = strings.Replace(tempStr, ".", string([]byte{separator}), 1)
+		}
+
+		if outBytes, err = formatNumber([]byte(tempStr), sign, annotationList, relativeAnnotatedLength, scale, separator); err != nil {
+			return []byte{}, currentByte, err
+		}
+		return outBytes, currentByte + relativeAnnotatedLength, nil
+	}
+
+	if number, isQualified := recordField.Interface().(QualifiedNumber); isQualified {
+		if !isValidQualifier(number.Qualifier) {
+			return []byte{}, currentByte, newInvalidQualifierError(number.Qualifier)
+		}
+		if len(number.Qualifier) > relativeAnnotatedLength {
+			return []byte{}, currentByte, newInvalidValueLengthError(number.String(), len(number.String()))
+		}
+		if _, hasNotation := getNotationAnnotation(annotationList); !hasNotation {
+			annotationList = append(annotationList[:len(annotationList):len(annotationList)], "notation:fixed") // not the scientific one of float64
+		}
+		tempBytes, _, err := marshalSimpleTypes(reflect.ValueOf(number.Value), false, relativeAnnotatedLength-len(number.Qualifier), annotationList, currentByte, depth, opts)
+		if err != nil {
+			return []byte{}, currentByte, err
+		}
+		return append([]byte(number.Qualifier), tempBytes...), currentByte + relativeAnnotatedLength, nil
+	}
+
 	var valueKind = reflect.TypeOf(recordField.Interface()).Kind()
 	switch valueKind {
 	case reflect.String:
 
 		var tempBytes = []byte(recordField.String())
 		if len(tempBytes) > relativeAnnotatedLength {
-			return []byte{}, currentByte, newInvalidValueLengthError(string(tempBytes), len(tempBytes))
-		} else if len(tempBytes) < relativeAnnotatedLength {
-			outBytes, _ = appendPaddingBytes(outBytes, relativeAnnotatedLength-len(tempBytes), byte(' '))
+			if !hasAnnotationTruncate(annotationList) {
+				return []byte{}, currentByte, newInvalidValueLengthError(string(tempBytes), len(tempBytes))
+			}
+			tempBytes = truncateBytes(tempBytes, relativeAnnotatedLength)
 		}
 
-		outBytes = append(outBytes, tempBytes...)
+		var alignment, _ = getAlignAnnotation(annotationList)
+		var fill, _ = getFillAnnotation(annotationList)
+		outBytes = alignBytes(tempBytes, relativeAnnotatedLength, alignment, fill)
 		currentByte += relativeAnnotatedLength
 
 	case reflect.Int:
@@ -236,12 +411,10 @@ func marshalSimpleTypes(recordField reflect.Value, onlyPaddWithZeros bool, relat
 			return []byte{}, currentByte, ErrorIntConversionOverflow
 		}
 
-		var isSignForced = hasAnnotationForceSign(annotationList)
 		var isNegative = tempInt < 0
-		if isNegative {
-			outBytes = append(outBytes, '-')
-		} else if isSignForced {
-			outBytes = append(outBytes, '+')
+		var sign, err = getNumberSign(tempInt, isNegative, annotationList)
+		if err != nil {
+			return []byte{}, currentByte, err
 		}
 
 		var tempBytes = []byte(strconv.Itoa(tempInt))
@@ -249,53 +422,38 @@ func marshalSimpleTypes(recordField reflect.Value, onlyPaddWithZeros bool, relat
 			tempBytes = tempBytes[1:]
 		}
 
-		var currLength = len(tempBytes)
-		if isNegative || isSignForced {
-			currLength++
-		}
-
-		if currLength > relativeAnnotatedLength {
-			return []byte{}, currentByte, newInvalidValueLengthError(string(append(outBytes, tempBytes...)), currLength)
-		} else if currLength < relativeAnnotatedLength {
-			var paddingByte byte
-			if hasAnnotationPadspace(annotationList) {
-				paddingByte = byte(' ')
-			} else {
-				paddingByte = byte('0')
-			}
-			outBytes, _ = appendPaddingBytes(outBytes, relativeAnnotatedLength-currLength, paddingByte)
+		if outBytes, err = formatNumber(tempBytes, sign, annotationList, relativeAnnotatedLength, -1, '.'); err != nil {
+			return []byte{}, currentByte, err
 		}
-
-		outBytes = append(outBytes, tempBytes...)
 		currentByte += relativeAnnotatedLength
 
 	case reflect.Float32, reflect.Float64:
 
-		var precision = -1
-		var err error
-		if precision, err = getPrecisionFromAnnotation(annotationList); err != nil {
-			return []byte{}, currentByte, err
-		}
-
 		var tempFloat = recordField.Float()
-		var tempStr string
-		if valueKind == reflect.Float32 {
-			tempStr = strconv.FormatFloat(tempFloat, 'f', precision, 32)
-		} else {
-			tempStr = strconv.FormatFloat(tempFloat, 'E', precision, 64)
-		}
-		if tempFloat == float64(int(tempFloat)) { // is truly an int?
-			if relativeAnnotatedLength > 1 {
-				tempStr += "."
+		if tempFloat < 0 { // rounded to zero, a negative number loses its sign, ex.: -0.001 with 'precision:2' is "0.00"
+			if text, err := formatFloat(tempFloat, recordField.Type().Bits(), annotationList, relativeAnnotatedLength, relativeAnnotatedLength); err == nil && isZeroText(text) {
+				tempFloat = math.Abs(tempFloat)
 			}
 		}
 
-		var isSignForced = hasAnnotationForceSign(annotationList)
 		var isNegative = tempFloat < 0
-		if isNegative {
-			outBytes = append(outBytes, '-')
-		} else if isSignForced {
-			outBytes = append(outBytes, '+')
+		var sign, err = getNumberSign(tempFloat, isNegative, annotationList)
+		if err != nil {
+			return []byte{}, currentByte, err
+		}
+
+		var available = relativeAnnotatedLength // the space for the number without a sign of its own, ex.: a forced one
+		if !isNegative {
+			available -= len(sign)
+		}
+
+		tempStr, err := formatFloat(tempFloat, recordField.Type().Bits(), annotationList, available, relativeAnnotatedLength)
+		if err != nil {
+			return []byte{}, currentByte, err
+		}
+		var separator = getDecimalSeparator(annotationList, opts)
+		if separator != '.' {
+			tempStr = strings.Replace(tempStr, ".", string([]byte{separator}), 1)
 		}
 
 		var tempBytes = []byte(tempStr)
@@ -303,24 +461,13 @@ func marshalSimpleTypes(recordField reflect.Value, onlyPaddWithZeros bool, relat
 			tempBytes = tempBytes[1:]
 		}
 
-		var currLength = len(tempBytes)
-		if isNegative || isSignForced {
-			currLength++
+		var precision, _ = getPrecisionFromAnnotation(annotationList) // already checked by formatFloat
+		if _, isImplied := getImpliedDecimals(annotationList); isImplied {
+			precision = 0 // there is no point to clamp around
 		}
-
-		if currLength > relativeAnnotatedLength {
-			return []byte{}, currentByte, newInvalidValueLengthError(string(append(outBytes, tempBytes...)), currLength)
-		} else if currLength < relativeAnnotatedLength {
-			var paddingByte byte
-			if hasAnnotationPadspace(annotationList) {
-				paddingByte = byte(' ')
-			} else {
-				paddingByte = byte('0')
-			}
-			outBytes, _ = appendPaddingBytes(outBytes, relativeAnnotatedLength-currLength, paddingByte)
+		if outBytes, err = formatNumber(tempBytes, sign, annotationList, relativeAnnotatedLength, precision, separator); err != nil {
+			return []byte{}, currentByte, err
 		}
-
-		outBytes = append(outBytes, tempBytes...)
 		currentByte += relativeAnnotatedLength
 
 	default:
@@ -330,3 +477,258 @@ func marshalSimpleTypes(recordField reflect.Value, onlyPaddWithZeros bool, relat
 
 	return outBytes, currentByte, nil
 }
+
+// Returns the text for a float according to the annotations 'notation', 'precision', 'expdigits' and 'nopoint'.
+// Without a 'notation' annotation, float32 is written in fixed and float64 in scientific notation.
+// Implied decimals (see getImpliedDecimals) are always written in fixed notation without the point.
+// 'length' is the space available, used by 'notation:auto' and for the trailing point. 'fieldLength' is the
+// size of the field, used for the trailing point without 'notation' and 'nopoint'.
+func formatFloat(value float64, bitSize int, annotationList []string, length int, fieldLength int) (string, error) {
+
+	var precision, err = getPrecisionFromAnnotation(annotationList)
+	if err != nil {
+		return "", err
+	}
+	expDigits, err := getExpDigitsAnnotation(annotationList)
+	if err != nil {
+		return "", err
+	}
+
+	var _, isImplied = getImpliedDecimals(annotationList)
+	var notation, hasNotation = getNotationAnnotation(annotationList)
+	if isImplied {
+		notation = "fixed" // the point can only be implied in fixed notation
+	} else if !hasNotation && bitSize == 64 {
+		notation = "sci"
+	}
+
+	var rounding, _ = getRoundingAnnotation(annotationList)
+
+	var text string
+	if notation != "sci" {
+		text = formatFixed(value, bitSize, precision, rounding)
+		// the trailing point marks the number as float, ex.: "6."
+		if hasNotation && !strings.Contains(text, ".") && length > len(text) && !hasAnnotationNoPoint(annotationList) {
+			text += "."
+		}
+	}
+	if notation == "sci" || (notation == "auto" && len(text) > length) {
+		text = formatScientific(value, bitSize, precision, expDigits, rounding)
+	}
+
+	// without 'notation' and 'nopoint' every integral value gets the trailing point, ex.: "6.00." or "6E+00."
+	if !hasNotation && !hasAnnotationNoPoint(annotationList) && value == float64(int(value)) && fieldLength > 1 {
+		text += "."
+	}
+
+	if isImplied {
+		text = strings.Replace(text, ".", "", 1) // ex.: "640" for 6.40
+	}
+
+	return text, nil
+}
+
+// Returns true if the number in 'text' has no other digit than zeros, ex.: "-0.00" or "-0.0E+00".
+func isZeroText(text string) bool {
+	if exponentStart := strings.IndexByte(text, 'E'); exponentStart >= 0 {
+		text = text[:exponentStart]
+	}
+	return strings.Trim(text, "-0.") == ""
+}
+
+// Returns the float in fixed notation with 'precision' decimal places, rounded according to the 'rounding' mode.
+// Without a mode, strconv.FormatFloat rounds the binary value - ex.: 2.675 is 2.67499... and becomes "2.67".
+func formatFixed(value float64, bitSize int, precision int, rounding string) string {
+
+	if rounding == "" || precision < 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+		return strconv.FormatFloat(value, 'f', precision, bitSize)
+	}
+
+	// the shortest text is the decimal number the float was meant to be
+	var intPart, fracPart = strconv.FormatFloat(math.Abs(value), 'f', -1, bitSize), ""
+	if point := strings.IndexByte(intPart, '.'); point >= 0 {
+		intPart, fracPart = intPart[:point], intPart[point+1:]
+	}
+
+	var digits, _ = roundDigits(intPart+fracPart, len(intPart)+precision, rounding)
+	var text = digits[:len(digits)-precision]
+	if precision > 0 {
+		text += "." + digits[len(digits)-precision:]
+	}
+	if value < 0 {
+		text = "-" + text
+	}
+
+	return text
+}
+
+// Returns the float in scientific notation with 'expDigits' digits for the exponent at least, ex.: "1.5E+003".
+// The mantissa is rounded like formatFixed does.
+func formatScientific(value float64, bitSize int, precision int, expDigits int, rounding string) string {
+
+	var text string
+	if rounding == "" || precision < 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+		text = strconv.FormatFloat(value, 'E', precision, bitSize)
+	} else {
+		text = roundScientific(value, bitSize, precision, rounding)
+	}
+
+	var exponentStart = strings.IndexByte(text, 'E') + 2 // after the sign of the exponent
+	if exponentStart < 2 {
+		return text // 'NaN' or 'Inf'
+	}
+
+	var digits = strings.TrimLeft(text[exponentStart:], "0")
+	for len(digits) < expDigits {
+		digits = "0" + digits
+	}
+
+	return text[:exponentStart] + digits
+}
+
+// Returns the float in scientific notation with 'precision' decimal places of the mantissa rounded according to the 'rounding' mode.
+func roundScientific(value float64, bitSize int, precision int, rounding string) string {
+
+	var text = strconv.FormatFloat(math.Abs(value), 'E', -1, bitSize) // ex.: "1.2345E+03"
+	var exponentStart = strings.IndexByte(text, 'E')
+	var exponent, _ = strconv.Atoi(text[exponentStart+1:])
+
+	var digits, isCarried = roundDigits(strings.Replace(text[:exponentStart], ".", "", 1), precision+1, rounding)
+	if isCarried { // ex.: "9.99" to "10.0" - shifted into "1.00" with the next exponent
+		digits = digits[:len(digits)-1]
+		exponent++
+	}
+
+	text = digits[:1]
+	if precision > 0 {
+		text += "." + digits[1:]
+	}
+	text += fmt.Sprintf("E%+03d", exponent)
+	if value < 0 {
+		text = "-" + text
+	}
+
+	return text
+}
+
+// Rounds the decimal 'digits' to the first 'keep' of them according to the 'rounding' mode: "half-up", "half-even" or "truncate".
+// Missing digits are added as zeros. Returns the digits and a bool which is true if rounding up added a digit, ex.: "999" to "1000".
+func roundDigits(digits string, keep int, rounding string) (string, bool) {
+
+	if keep >= len(digits) {
+		return digits + strings.Repeat("0", keep-len(digits)), false
+	}
+
+	var kept, rest = []byte(digits[:keep]), digits[keep:]
+
+	var isRoundedUp bool
+	switch rounding {
+	case "half-up":
+		isRoundedUp = rest[0] >= '5'
+	case "half-even":
+		var isTie = rest[0] == '5' && strings.TrimRight(rest[1:], "0") == ""
+		var isOdd = keep > 0 && (kept[keep-1]-'0')%2 == 1
+		isRoundedUp = rest[0] > '5' || (rest[0] == '5' && !isTie) || (isTie && isOdd)
+	}
+	if !isRoundedUp {
+		return string(kept), false
+	}
+
+	for i := keep - 1; i >= 0; i-- {
+		if kept[i] != '9' {
+			kept[i]++
+			return string(kept), false
+		}
+		kept[i] = '0'
+	}
+
+	return "1" + string(kept), true
+}
+
+// Returns the bytes for a number that doesn't fit into 'length' bytes according to the 'overflow' annotation along with a bool
+// which is true if they are written instead of failing. (', ok' idiom) 'sign' holds the sign written before the number, if any.
+// 'precision' is the number of decimal places of a float - clamping to ints and floats without precision writes nines only.
+func formatOverflow(annotationList []string, length int, sign []byte, precision int, separator byte) ([]byte, bool) {
+
+	switch overflow, _ := getOverflowAnnotation(annotationList); overflow {
+	case "fill":
+
+		var outBytes, _ = appendPaddingBytes([]byte{}, length, '*')
+		return outBytes, true
+
+	case "clamp": // the maximum representable value
+
+		var intDigits = length - len(sign)
+		if precision > 0 {
+			intDigits -= precision + 1
+		}
+		if intDigits < 1 {
+			return nil, false // not even a single digit fits in front of the point
+		}
+
+		var outBytes, _ = appendPaddingBytes(append([]byte{}, sign...), intDigits, '9')
+		if precision > 0 {
+			outBytes = append(outBytes, separator)
+			outBytes, _ = appendPaddingBytes(outBytes, precision, '9')
+		}
+		return outBytes, true
+	}
+
+	return nil, false
+}
+
+// Returns the sign written for a number according to the annotations 'sign' and 'forcesign' - empty if there is none.
+// Gives an ErrorInvalidSign for negative numbers with 'sign:none' - 'value' is the number for the error.
+func getNumberSign(value interface{}, isNegative bool, annotationList []string) ([]byte, error) {
+
+	var signMode, _ = getSignAnnotation(annotationList)
+
+	switch {
+	case isNegative && signMode == "none":
+		return nil, newInvalidSignError(fmt.Sprint(value), signMode)
+	case isNegative:
+		return []byte{'-'}, nil
+	case hasAnnotationForceSign(annotationList):
+		return []byte{'+'}, nil
+	case signMode == "space" || signMode == "trailing": // the sign has its own byte
+		return []byte{' '}, nil
+	}
+
+	return []byte{}, nil
+}
+
+// Returns the 'digits' of a number with its 'sign' and padding in 'length' bytes according to the annotations
+// 'sign', 'padspace' and 'overflow'. 'precision' and 'separator' are used for clamping floats. (see formatOverflow)
+func formatNumber(digits []byte, sign []byte, annotationList []string, length int, precision int, separator byte) ([]byte, error) {
+
+	var signMode, _ = getSignAnnotation(annotationList)
+	var isTrailing = signMode == "trailing"
+
+	var currLength = len(digits) + len(sign)
+	if currLength > length {
+		if overflowBytes, isHandled := formatOverflow(annotationList, length, sign, precision, separator); isHandled {
+			if isTrailing { // the sign moves behind the nines
+				overflowBytes = append(overflowBytes[len(sign):], sign...)
+			}
+			return overflowBytes, nil
+		}
+		return nil, newInvalidValueLengthError(string(sign)+string(digits), currLength)
+	}
+
+	var paddingByte = byte('0')
+	if hasAnnotationPadspace(annotationList) {
+		paddingByte = byte(' ')
+	}
+
+	var outBytes = make([]byte, 0, length)
+	if !isTrailing {
+		outBytes = append(outBytes, sign...)
+	}
+	outBytes, _ = appendPaddingBytes(outBytes, length-currLength, paddingByte)
+	outBytes = append(outBytes, digits...)
+	if isTrailing {
+		outBytes = append(outBytes, sign...)
+	}
+
+	return outBytes, nil
+}
@@ -518,3 +518,773 @@ func TestMasrshalDynamicArray(t *testing.T) {
 	_ = result
 	assert.Equal(t, true, errors.Is(err, ErrorUnknownFieldName))
 }
+
+//
+//-Code Table------------------------------------------------------------------
+
+type testResultFlagMarshal string
+
+const (
+	testResultFlagNormalMarshal testResultFlagMarshal = "normal"
+	testResultFlagHighMarshal   testResultFlagMarshal = "high"
+	testResultFlagLowMarshal    testResultFlagMarshal = "low"
+)
+
+type testCodeTableMarshal struct {
+	Flag     testResultFlagMarshal   `bin:":2"`
+	Flags    []testResultFlagMarshal `bin:"array:2,:1"`
+	Other    testResultFlagMarshal   `bin:":2,fallback:OtherRaw"`
+	OtherRaw string
+}
+
+func TestMarshalCodeTable(t *testing.T) {
+
+	var err = RegisterCodeTable(map[testResultFlagMarshal]string{
+		testResultFlagNormalMarshal: "N",
+		testResultFlagHighMarshal:   "H",
+		testResultFlagLowMarshal:    "L",
+	})
+	assert.Nil(t, err)
+
+	var inputData = testCodeTableMarshal{
+		Flag:     testResultFlagHighMarshal,
+		Flags:    []testResultFlagMarshal{testResultFlagLowMarshal, testResultFlagNormalMarshal},
+		Other:    "unknown",
+		OtherRaw: "X",
+	}
+
+	result, err := Marshal(inputData, 'x', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []byte(" HLN X"), result)
+
+	//-------------------------------------------------------------------------
+
+	inputData.Other = "unknown"
+	inputData.OtherRaw = ""
+
+	_, err = Marshal(inputData, 'x', EncodingUTF8, TimezoneUTC, "\r")
+
+	var errUnknownCode *ErrorUnknownCode
+	assert.Equal(t, true, errors.As(err, &errUnknownCode))
+	assert.Equal(t, "unknown", errUnknownCode.Code)
+}
+
+//
+//-Conditional Fields----------------------------------------------------------
+
+type testConditionalMarshal struct {
+	SampleType string                      `bin:":1"`
+	SampleId   string                      `bin:":4"`
+	QCLevel    int                         `bin:":1,if:SampleType=Q"`
+	QCBlock    testConditionalInnerMarshal `bin:"if:SampleType=Q"`
+	Result     string                      `bin:":3"`
+}
+
+type testConditionalInnerMarshal struct {
+	Lot string `bin:":2"`
+}
+
+type testConditionalMalformedMarshal struct {
+	SampleType string `bin:":1"`
+	QCLevel    int    `bin:":1,if:SampleType"`
+}
+
+func TestMarshalConditionalFields(t *testing.T) {
+
+	var inputData = testConditionalMarshal{
+		SampleType: "Q",
+		SampleId:   "0001",
+		QCLevel:    2,
+		QCBlock:    testConditionalInnerMarshal{Lot: "L1"},
+		Result:     "123",
+	}
+
+	result, err := Marshal(inputData, 'x', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []byte("Q00012L1123"), result)
+
+	//-------------------------------------------------------------------------
+
+	inputData.SampleType = "P"
+
+	result, err = Marshal(inputData, 'x', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []byte("P0001123"), result)
+
+	//-------------------------------------------------------------------------
+
+	_, err = Marshal(testConditionalMalformedMarshal{SampleType: "Q"}, 'x', EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Equal(t, true, errors.Is(err, ErrorMalformedCondition))
+}
+
+//
+//-Default Values--------------------------------------------------------------
+
+type testDefaultMarshal struct {
+	BlankWhenZero  int     `bin:":3,default:0,blankdefault"`
+	NotDefault     int     `bin:":3,default:0,blankdefault"`
+	OnlyDefault    int     `bin:":3,default:0"`
+	BlankWhenFloat float32 `bin:":4,default:1.5,blankdefault"`
+}
+
+func TestMarshalDefaultValues(t *testing.T) {
+
+	var inputData = testDefaultMarshal{
+		BlankWhenZero:  0,
+		NotDefault:     12,
+		OnlyDefault:    0,
+		BlankWhenFloat: 1.5,
+	}
+
+	result, err := Marshal(inputData, 'x', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []byte("   012000    "), result)
+
+	//-------------------------------------------------------------------------
+
+	// a blanked field doesn't move the positions behind it
+	type testDefaultOffsetMarshal struct {
+		A int `bin:":1"`
+		B int `bin:":3,default:0,blankdefault"`
+		C int `bin:"4:2"`
+	}
+
+	result, err = Marshal(testDefaultOffsetMarshal{A: 1, B: 0, C: 2}, 'x', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("1   02"), result)
+}
+
+//
+//-Validation------------------------------------------------------------------
+
+type testValidationMarshal struct {
+	SampleId   string                       `bin:":6,required,pattern:^S\\d+$"`
+	RackNumber int                          `bin:":4,min:1,max:9999"`
+	Results    []testValidationInnerMarshal `bin:"array:terminator"`
+}
+
+type testValidationInnerMarshal struct {
+	TestCode string `bin:":2,oneof:61|62|63"`
+}
+
+func TestMarshalValidation(t *testing.T) {
+
+	var inputData = testValidationMarshal{
+		SampleId:   "S123",
+		RackNumber: 1,
+		Results:    []testValidationInnerMarshal{{TestCode: "61"}, {TestCode: "62"}},
+	}
+
+	result, err := Marshal(inputData, 'x', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []byte("  S12300016162\r"), result)
+
+	//-------------------------------------------------------------------------
+
+	var inputDataInvalid = testValidationMarshal{
+		SampleId:   "",
+		RackNumber: 0,
+		Results:    []testValidationInnerMarshal{{TestCode: "61"}, {TestCode: "99"}},
+	}
+
+	_, err = Marshal(inputDataInvalid, 'x', EncodingUTF8, TimezoneUTC, "\r")
+
+	var errValidation *ErrorValidation
+	assert.Equal(t, true, errors.As(err, &errValidation))
+	assert.Equal(t, "required", errValidation.Rule)
+
+	//-------------------------------------------------------------------------
+
+	_, err = Marshal(inputDataInvalid, 'x', EncodingUTF8, TimezoneUTC, "\r", CollectValidationErrors())
+
+	var errCollection *ErrorCollection
+	assert.Equal(t, true, errors.As(err, &errCollection))
+	assert.Equal(t, 3, len(errCollection.Errors)) // required, min and oneof
+}
+
+//
+//-Error Positions-------------------------------------------------------------
+
+type testErrorPositionMarshal struct {
+	RecordType string                          `bin:":1"`
+	Results    []testErrorPositionInnerMarshal `bin:"array:terminator"`
+}
+
+type testErrorPositionInnerMarshal struct {
+	TestCode string `bin:":2"`
+	Value    int    `bin:":3"`
+}
+
+func TestMarshalErrorPositions(t *testing.T) {
+
+	var inputData = []testErrorPositionMarshal{
+		{RecordType: "D", Results: []testErrorPositionInnerMarshal{{TestCode: "61", Value: 1}}},
+		{RecordType: "D", Results: []testErrorPositionInnerMarshal{{TestCode: "61", Value: 1}, {TestCode: "62", Value: 1000}}},
+	}
+
+	_, err := Marshal(inputData, 'x', EncodingUTF8, TimezoneUTC, "\r")
+
+	var errInvalidValueLength *ErrorInvalidValueLength
+	assert.Equal(t, true, errors.Is(err, errInvalidValueLength))
+
+	path, _ := FieldPathOf(err)
+	assert.Equal(t, "Results[1].Value", path)
+
+	offset, _ := OffsetOf(err)
+	assert.Equal(t, 16, offset) // "D61001\r\r" + "D61001" + "62"
+
+	recordIndex, _ := RecordIndexOf(err)
+	assert.Equal(t, 1, recordIndex)
+}
+
+//
+//-Annotation Grammar----------------------------------------------------------
+
+type testAnnotationGrammarMarshal struct {
+	Code    string `bin:" :2 "`
+	Comment string `bin:"0x04:5 , default:'a, b', blankdefault"`
+}
+
+type testUnknownAnnotationMarshal struct {
+	Code string `bin:":2,padspaces"`
+}
+
+func TestMarshalAnnotationGrammar(t *testing.T) {
+
+	result, err := Marshal(testAnnotationGrammarMarshal{Code: "AB", Comment: "a, b"}, 'x', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, "ABxx     ", string(result))
+
+	//-------------------------------------------------------------------------
+
+	_, err = Marshal(testUnknownAnnotationMarshal{Code: "AB"}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+
+	var errInvalidAnnotation *ErrorInvalidAnnotation
+	assert.Equal(t, true, errors.As(err, &errInvalidAnnotation))
+	assert.Equal(t, "padspaces", errInvalidAnnotation.Annotation)
+	path, _ := FieldPathOf(err)
+	assert.Equal(t, "Code", path)
+}
+
+//
+//-Alignment-------------------------------------------------------------------
+
+type testAlignmentMarshal struct {
+	Right     string `bin:":5"`
+	Left      string `bin:":5,align:left"`
+	Center    string `bin:":6,align:center,fill:_"`
+	ZeroFill  string `bin:":5,fill:0"`
+	Comment   string `bin:":8,align:left,truncate"`
+	Multibyte string `bin:":5,align:left,truncate"`
+}
+
+type testTooLongMarshal struct {
+	Comment string `bin:":4,align:left"`
+}
+
+func TestMarshalAlignment(t *testing.T) {
+
+	result, err := Marshal(testAlignmentMarshal{
+		Right:     "AB",
+		Left:      "AB",
+		Center:    "ABC",
+		ZeroFill:  "A12",
+		Comment:   "sample hemolytic",
+		Multibyte: "Grüße",
+	}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "   ABAB   _ABC__00A12sample hGrü ", string(result))
+
+	//-------------------------------------------------------------------------
+
+	_, err = Marshal(testTooLongMarshal{Comment: "sample hemolytic"}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+
+	var errInvalidValueLength *ErrorInvalidValueLength
+	assert.Equal(t, true, errors.As(err, &errInvalidValueLength))
+}
+
+//
+//-Float Notation--------------------------------------------------------------
+
+type testFloatNotationMarshal struct {
+	Fixed64      float64 `bin:":8,notation:fixed,precision:2"`
+	Scientific32 float32 `bin:":9,notation:sci,precision:2"`
+	ExpDigits    float64 `bin:":10,precision:2,expdigits:3"`
+	ShortExp     float64 `bin:":6,precision:1,expdigits:1"`
+	AutoFits     float64 `bin:":8,notation:auto,padspace"`
+	AutoTooLong  float64 `bin:":8,notation:auto,precision:1"`
+	Point        float32 `bin:":4"`
+	NoPoint      float32 `bin:":4,nopoint"`
+	NoPoint64    float64 `bin:":4,notation:fixed,nopoint"`
+	Precision    float32 `bin:":5,notation:fixed,precision:2"`
+}
+
+func TestMarshalFloatNotation(t *testing.T) {
+
+	var result, err = Marshal(testFloatNotationMarshal{
+		Fixed64:      1234.567,
+		Scientific32: 1234.567,
+		ExpDigits:    -1234.567,
+		ShortExp:     1234.567,
+		AutoFits:     1234.5,
+		AutoTooLong:  123456789,
+		Point:        12,
+		NoPoint:      12,
+		NoPoint64:    12,
+		Precision:    6,
+	}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "01234.5701.23E+03-1.23E+0031.2E+3  1234.501.2E+08012.0012001206.00", string(result))
+
+	//-------------------------------------------------------------------------
+
+	type testInvalidExpDigits struct {
+		Value float64 `bin:":10,expdigits:4"`
+	}
+	_, err = Marshal(testInvalidExpDigits{Value: 1}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Equal(t, true, errors.Is(err, ErrorInvalidAnnotationValue))
+
+	//-------------------------------------------------------------------------
+
+	var parsed testFloatNotationMarshal
+	_, err = Unmarshal(result, &parsed, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, float64(-1230), parsed.ExpDigits)
+	assert.Equal(t, float64(120000000), parsed.AutoTooLong)
+}
+
+type testFloatDefaultMarshal struct {
+	Float32   float32 `bin:":4,precision:0"`
+	Integral  float32 `bin:":4"`
+	Precision float32 `bin:":5,precision:2"`
+	Float64   float64 `bin:":8"`
+	OneByte   float32 `bin:":1"`
+}
+
+func TestMarshalFloatDefaults(t *testing.T) {
+
+	// without 'notation' and 'nopoint' floats are written as they always were
+	var result, err = Marshal(testFloatDefaultMarshal{
+		Float32:   6.4,
+		Integral:  12,
+		Precision: 6,
+		Float64:   6,
+		OneByte:   6,
+	}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "0006012.6.00.006E+00.6", string(result))
+}
+
+//
+//-Decimal Separator-----------------------------------------------------------
+
+type testDecimalSeparatorMarshal struct {
+	Default float32 `bin:":4"`
+	Comma   float32 `bin:":4,decimal:','"`
+	Double  float64 `bin:":9,decimal:',',precision:2"`
+}
+
+func TestMarshalDecimalSeparator(t *testing.T) {
+
+	var inputData = testDecimalSeparatorMarshal{Default: 6.4, Comma: 6.4, Double: -1234.5}
+
+	result, err := Marshal(inputData, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, "06.406,4-1,23E+03", string(result))
+
+	result, err = Marshal(inputData, ' ', EncodingUTF8, TimezoneUTC, "\r", DecimalSeparator(','))
+	assert.Nil(t, err)
+	assert.Equal(t, "06,406,4-1,23E+03", string(result))
+}
+
+//
+//-Rounding--------------------------------------------------------------------
+
+type testRoundingMarshal struct {
+	Default    float64 `bin:":5,notation:fixed,precision:2"`
+	HalfUp     float64 `bin:":5,notation:fixed,precision:2,rounding:half-up"`
+	HalfEven   float64 `bin:":5,notation:fixed,precision:2,rounding:half-even"`
+	HalfEven2  float64 `bin:":5,notation:fixed,precision:2,rounding:half-even"`
+	Truncate   float32 `bin:":5,precision:2,rounding:truncate"`
+	Negative   float32 `bin:":5,precision:1,rounding:half-up"`
+	Carry      float32 `bin:":5,precision:2,rounding:half-up"`
+	Scientific float64 `bin:":8,notation:sci,precision:2,rounding:half-up"`
+	SciCarry   float64 `bin:":8,notation:sci,precision:2,rounding:half-even"`
+}
+
+func TestMarshalRounding(t *testing.T) {
+
+	var result, err = Marshal(testRoundingMarshal{
+		Default:    2.675, // is 2.67499999... in binary
+		HalfUp:     2.675,
+		HalfEven:   2.665,
+		HalfEven2:  2.675,
+		Truncate:   2.679,
+		Negative:   -2.25,
+		Carry:      9.995,
+		Scientific: 1235,
+		SciCarry:   9996,
+	}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "02.6702.6802.6602.6802.67-02.310.001.24E+031.00E+04", string(result))
+}
+
+type testNegativeZeroMarshal struct {
+	Default   float32 `bin:":8,precision:2"`
+	HalfUp    float64 `bin:":6,notation:fixed,precision:1,rounding:half-up"`
+	Sci       float64 `bin:":8,precision:1"`
+	NoSign    float32 `bin:":4,precision:1,sign:none"`
+	NotZero   float32 `bin:":5,precision:2"`
+	Trailing  float32 `bin:":5,precision:2,sign:trailing"`
+	Separator float32 `bin:":5,precision:2,decimal:','"`
+}
+
+func TestMarshalNegativeZero(t *testing.T) {
+
+	var result, err = Marshal(testNegativeZeroMarshal{
+		Default:   -0.001,
+		HalfUp:    -0.04,
+		Sci:       -0.00001,
+		NoSign:    -0.01,
+		NotZero:   -0.006,
+		Trailing:  -0.001,
+		Separator: -0.001,
+	}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "00000.000000.0-1.0E-0500.0-0.010.00 00,00", string(result))
+}
+
+//
+//-Overflow--------------------------------------------------------------------
+
+type testOverflowMarshal struct {
+	Error       int     `bin:":3,overflow:error"`
+	ClampInt    int     `bin:":3,overflow:clamp"`
+	ClampNeg    int     `bin:":3,overflow:clamp"`
+	ClampSign   int     `bin:":3,overflow:clamp,forcesign"`
+	FillInt     int     `bin:":3,overflow:fill"`
+	ClampFloat  float32 `bin:":5,precision:2,overflow:clamp"`
+	ClampComma  float32 `bin:":5,precision:1,overflow:clamp,decimal:','"`
+	FillFloat   float64 `bin:":6,precision:2,overflow:fill"`
+	NotOverflow float32 `bin:":5,precision:2,overflow:fill"`
+}
+
+func TestMarshalOverflow(t *testing.T) {
+
+	var inputData = testOverflowMarshal{
+		Error:       12,
+		ClampInt:    1234,
+		ClampNeg:    -1234,
+		ClampSign:   123,
+		FillInt:     1234,
+		ClampFloat:  123.456,
+		ClampComma:  -123.45,
+		FillFloat:   12345.6,
+		NotOverflow: 1.5,
+	}
+
+	var result, err = Marshal(inputData, ' ', EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "012999-99+99***99.99-99,9******01.50", string(result))
+
+	//-------------------------------------------------------------------------
+
+	inputData.Error = 1234
+	_, err = Marshal(inputData, ' ', EncodingUTF8, TimezoneUTC, "\r")
+
+	var errInvalidValueLength *ErrorInvalidValueLength
+	assert.Equal(t, true, errors.As(err, &errInvalidValueLength))
+
+	type testClampTooShort struct {
+		Value float32 `bin:":3,precision:2,overflow:clamp"`
+	}
+	_, err = Marshal(testClampTooShort{Value: 12}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Equal(t, true, errors.As(err, &errInvalidValueLength))
+}
+
+//
+//-Sign------------------------------------------------------------------------
+
+type testSignMarshal struct {
+	Leading        int     `bin:":4,sign:leading"`
+	Trailing       int     `bin:":4,sign:trailing"`
+	TrailingPlus   int     `bin:":4,sign:trailing"`
+	TrailingForced int     `bin:":4,sign:trailing,forcesign"`
+	Space          int     `bin:":4,sign:space"`
+	SpaceNegative  int     `bin:":4,sign:space,padspace"`
+	None           int     `bin:":4,sign:none"`
+	FloatTrailing  float32 `bin:":6,sign:trailing,precision:2"`
+	ClampTrailing  int     `bin:":3,sign:trailing,overflow:clamp"`
+}
+
+func TestMarshalSign(t *testing.T) {
+
+	var inputData = testSignMarshal{
+		Leading:        -12,
+		Trailing:       -12,
+		TrailingPlus:   12,
+		TrailingForced: 12,
+		Space:          12,
+		SpaceNegative:  -12,
+		None:           12,
+		FloatTrailing:  -1.5,
+		ClampTrailing:  -1234,
+	}
+
+	var result, err = Marshal(inputData, ' ', EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "-012012-012 012+ 012- 12001201.50-99-", string(result))
+
+	//-------------------------------------------------------------------------
+
+	inputData.None = -12
+	_, err = Marshal(inputData, ' ', EncodingUTF8, TimezoneUTC, "\r")
+
+	var errInvalidSign *ErrorInvalidSign
+	assert.Equal(t, true, errors.As(err, &errInvalidSign))
+	assert.Equal(t, "-12", errInvalidSign.Value)
+	path, _ := FieldPathOf(err)
+	assert.Equal(t, "None", path)
+}
+
+//
+//-Placeholders----------------------------------------------------------------
+
+type testPlaceholderMarshal struct {
+	Missing *float32 `bin:":5,placeholder:*****|-----"`
+	Present *float32 `bin:":5,precision:2,placeholder:*****"`
+	Blank   *int     `bin:":3"`
+	Short   *int     `bin:":4,placeholder:NA,align:left"`
+	Zero    int      `bin:":3,placeholder:***"`
+}
+
+func TestMarshalPlaceholders(t *testing.T) {
+
+	var value = float32(1.5)
+
+	result, err := Marshal(testPlaceholderMarshal{Present: &value}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, "*****01.50   NA  000", string(result))
+
+	//-------------------------------------------------------------------------
+
+	type testPlaceholderTooLong struct {
+		Missing *int `bin:":2,placeholder:NaN"`
+	}
+	_, err = Marshal(testPlaceholderTooLong{}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+
+	var errInvalidValueLength *ErrorInvalidValueLength
+	assert.Equal(t, true, errors.As(err, &errInvalidValueLength))
+}
+
+//
+//-Qualified Numbers-----------------------------------------------------------
+
+type testQualifiedNumberMarshal struct {
+	Below    QualifiedNumber   `bin:":6,precision:2"`
+	Above    QualifiedNumber   `bin:":6"`
+	Padded   QualifiedNumber   `bin:":6,padspace,precision:1"`
+	Comma    QualifiedNumber   `bin:":6,decimal:',',precision:2"`
+	Negative QualifiedNumber   `bin:":6,sign:trailing"`
+	Results  []QualifiedNumber `bin:"array:2,:3,nopoint"`
+}
+
+func TestMarshalQualifiedNumbers(t *testing.T) {
+
+	var record = testQualifiedNumberMarshal{
+		Below:    QualifiedNumber{Qualifier: "<", Value: 0.5},
+		Above:    QualifiedNumber{Qualifier: ">=", Value: 1000, Raw: "ignored"},
+		Padded:   QualifiedNumber{Qualifier: "~", Value: 1.5},
+		Comma:    QualifiedNumber{Value: 12.5},
+		Negative: QualifiedNumber{Qualifier: "<", Value: -12},
+		Results:  []QualifiedNumber{{Qualifier: "<=", Value: 5}},
+	}
+
+	result, err := Marshal(record, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, "<00.50>=1000~  1.5012,50<012.-<=5\x00\x00\x00", string(result))
+
+	var reread testQualifiedNumberMarshal
+	_, err = Unmarshal(result, &reread, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, record.Below.Value, reread.Below.Value)
+	assert.Equal(t, ">=", reread.Above.Qualifier)
+
+	//-------------------------------------------------------------------------
+
+	_, err = Marshal(testQualifiedNumberMarshal{Below: QualifiedNumber{Qualifier: "!"}}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Equal(t, true, errors.Is(err, &ErrorInvalidQualifier{}))
+
+	_, err = Marshal(testQualifiedNumberMarshal{Above: QualifiedNumber{Qualifier: ">", Value: 100000}}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	var errInvalidValueLength *ErrorInvalidValueLength
+	assert.Equal(t, true, errors.As(err, &errInvalidValueLength))
+}
+
+//
+//-Decimals--------------------------------------------------------------------
+
+type testDecimalMarshal struct {
+	Scaled   Decimal   `bin:":6"`
+	Rounded  Decimal   `bin:":5,precision:1"`
+	HalfUp   Decimal   `bin:":5,precision:1,rounding:half-up"`
+	Extended Decimal   `bin:":6,precision:3,forcesign"`
+	Padded   Decimal   `bin:":6,padspace"`
+	Comma    Decimal   `bin:":6,decimal:','"`
+	Clamped  Decimal   `bin:":5,precision:2,overflow:clamp"`
+	Values   []Decimal `bin:"array:2,:3"`
+}
+
+func TestMarshalDecimals(t *testing.T) {
+
+	var record = testDecimalMarshal{
+		Scaled:   NewDecimal(640, 2),
+		Rounded:  NewDecimal(-225, 2),
+		HalfUp:   NewDecimal(225, 2),
+		Extended: NewDecimal(15, 1),
+		Padded:   NewDecimal(-31, 1),
+		Comma:    NewDecimal(1250, 2),
+		Clamped:  NewDecimal(123456, 2),
+		Values:   []Decimal{NewDecimal(5, 1), {}},
+	}
+
+	result, err := Marshal(record, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, "006.40-02.2002.3+1.500-  3.1012,5099.990.5000", string(result))
+
+	//-------------------------------------------------------------------------
+
+	// digits and scale survive the round trip
+	type testDecimalRoundTrip struct {
+		Values []Decimal `bin:"array:4,:24"`
+	}
+	var data = "000000000000000000006.400.1000000000000000000000000000000000000000000001-0.000000000000000000001"
+
+	var roundTrip testDecimalRoundTrip
+	_, err = Unmarshal([]byte(data), &roundTrip, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+
+	result, err = Marshal(roundTrip, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, data, string(result))
+
+	//-------------------------------------------------------------------------
+
+	_, err = Marshal(testDecimalMarshal{Scaled: NewDecimal(1234567, 2)}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	var errInvalidValueLength *ErrorInvalidValueLength
+	assert.Equal(t, true, errors.As(err, &errInvalidValueLength))
+}
+
+//
+//-Implied decimals------------------------------------------------------------
+
+type testImpliedDecimalMarshal struct {
+	Decimal  Decimal `bin:":5,nopoint,precision:2"`
+	Rounded  Decimal `bin:":4,nopoint,precision:1"`
+	Float    float32 `bin:":5,nopoint,precision:2"`
+	Signed   float64 `bin:":6,nopoint,precision:3,forcesign"`
+	Padded   float64 `bin:":5,nopoint,precision:2,padspace"`
+	Clamped  float32 `bin:":4,nopoint,precision:2,overflow:clamp"`
+	Comma    Decimal `bin:":5,nopoint,precision:2,decimal:','"`
+	Integral float32 `bin:":3,nopoint,precision:0"`
+}
+
+func TestMarshalImpliedDecimals(t *testing.T) {
+
+	var record = testImpliedDecimalMarshal{
+		Decimal:  NewDecimal(640, 2),
+		Rounded:  NewDecimal(-225, 2),
+		Float:    6.4,
+		Signed:   -1.5,
+		Padded:   0.05,
+		Clamped:  123.456,
+		Comma:    NewDecimal(125, 1),
+		Integral: 12,
+	}
+
+	result, err := Marshal(record, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, "00640-02200640-01500  005999901250012", string(result))
+
+	//-------------------------------------------------------------------------
+
+	// the scale is applied again on unmarshaling
+	var roundTrip testImpliedDecimalMarshal
+	_, err = Unmarshal(result, &roundTrip, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, "6.40", roundTrip.Decimal.String())
+	assert.Equal(t, "-2.2", roundTrip.Rounded.String())
+	assert.Equal(t, float32(6.4), roundTrip.Float)
+	assert.Equal(t, -1.5, roundTrip.Signed)
+	assert.Equal(t, 0.05, roundTrip.Padded)
+	assert.Equal(t, float32(99.99), roundTrip.Clamped)
+	assert.Equal(t, "12.50", roundTrip.Comma.String())
+	assert.Equal(t, float32(12), roundTrip.Integral)
+
+	again, err := Marshal(roundTrip, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, string(result), string(again))
+
+	//-------------------------------------------------------------------------
+
+	// implied decimals are always written in fixed notation
+	type testImpliedDecimalNotation struct {
+		Value float64 `bin:":6,notation:sci,nopoint,precision:2"`
+	}
+	result, err = Marshal(testImpliedDecimalNotation{Value: 1234.5}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, "123450", string(result))
+}
+
+//
+//-Nulls-----------------------------------------------------------------------
+
+type testNullMarshal struct {
+	Number  Null[int]             `bin:":3"`
+	Missing Null[int]             `bin:":3"`
+	Text    Null[string]          `bin:":4,align:right,trim"`
+	Float   Null[float32]         `bin:":5,precision:2,placeholder:*****|-----"`
+	Absent  Null[float64]         `bin:":5,placeholder:*****|-----"`
+	Decimal Null[Decimal]         `bin:":5"`
+	Result  Null[QualifiedNumber] `bin:":5,precision:1"`
+	Values  []Null[int]           `bin:"array:2,:2"`
+}
+
+func TestMarshalNulls(t *testing.T) {
+
+	var record = testNullMarshal{
+		Number:  NewNull(12),
+		Text:    NewNull("ab"),
+		Float:   NewNull(float32(1.5)),
+		Absent:  Null[float64]{Value: 1, Valid: false},
+		Decimal: NewNull(NewDecimal(640, 2)),
+		Result:  NewNull(QualifiedNumber{Qualifier: ">", Value: 10}),
+		Values:  []Null[int]{{}, NewNull(3)},
+	}
+
+	result, err := Marshal(record, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, "012     ab01.50*****06.40>10.0  03", string(result))
+
+	var reread testNullMarshal
+	_, err = Unmarshal(result, &reread, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, record.Number, reread.Number)
+	assert.Equal(t, record.Missing, reread.Missing)
+	assert.Equal(t, record.Text, reread.Text)
+	assert.Equal(t, record.Float, reread.Float)
+	assert.Equal(t, Null[float64]{}, reread.Absent)
+	assert.Equal(t, record.Values, reread.Values)
+}
@@ -0,0 +1,67 @@
+package binfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// A Null is a value which may be absent, like the sql.Null types: Valid is false for a blank field or one
+// with a 'placeholder' literal, and Value is the zero value then. T can be any type a field can have otherwise,
+// ex.: Null[int], Null[string] or Null[Decimal].
+//
+// Marshaling writes the first 'placeholder' literal - or spaces - for an invalid value.
+type Null[T any] struct {
+	Value T
+	Valid bool
+}
+
+// Returns a valid Null holding 'value'.
+func NewNull[T any](value T) Null[T] {
+	return Null[T]{Value: value, Valid: true}
+}
+
+// Returns the value as text or "" if it is not valid.
+func (n Null[T]) String() string {
+	if !n.Valid {
+		return ""
+	}
+	return fmt.Sprint(n.Value)
+}
+
+// Encodes the value as JSON or null if it is not valid.
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
+
+// Decodes the value from JSON, null is an invalid value.
+func (n *Null[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*n = Null[T]{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &n.Value); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// Implements nullable.
+func (n Null[T]) isNullable() {}
+
+// A nullable is implemented by every Null[T], to recognize them in reflection.
+type nullable interface {
+	isNullable()
+}
+
+var nullableType = reflect.TypeOf((*nullable)(nil)).Elem()
+
+// Returns true if 'valueType' is a Null[T].
+func isNullType(valueType reflect.Type) bool {
+	return valueType.Kind() == reflect.Struct && valueType.Implements(nullableType)
+}
@@ -0,0 +1,158 @@
+package binfile
+
+import "errors"
+
+// An Option changes the default behaviour of Marshal and Unmarshal.
+type Option func(*options)
+
+// Holds the behaviour requested by the options of a single Marshal or Unmarshal call.
+type options struct {
+	collectValidationErrors bool
+	lenient                 bool
+	strict                  bool
+	maxArrayLength          int
+	maxDepth                int
+	maxRecordBytes          int
+	recordStartByte         int
+	decimalSeparator        byte
+	thousandsSeparator      byte
+	trace                   *dumpTrace
+}
+
+// Applies the provided options on top of the defaults.
+func newOptions(optionList []Option) *options {
+	var opts = &options{}
+	for _, option := range optionList {
+		option(opts)
+	}
+	return opts
+}
+
+// CollectValidationErrors makes Marshal and Unmarshal continue after a failed validation annotation.
+// All violations are then returned at once in an ErrorCollection.
+func CollectValidationErrors() Option {
+	return func(opts *options) {
+		opts.collectValidationErrors = true
+	}
+}
+
+// Lenient makes Unmarshal decode everything it can instead of failing on the first problem.
+// Fields that fail are left at their zero value. Records of a top-level array that can't be read
+// to their end are skipped up to the next terminator. All problems are returned at once in an ErrorCollection.
+//
+// NOTE: This has no effect on Marshal.
+func Lenient() Option {
+	return func(opts *options) {
+		opts.lenient = true
+	}
+}
+
+// Strict makes Unmarshal reject records that don't end exactly at the end of their layout.
+// Only a terminator may follow the last field of a struct. Each record of a top-level array has to be followed
+// by the terminator or the end of the input. The surplus bytes are returned in an ErrorTrailingBytes.
+//
+// NOTE: This has no effect on Marshal.
+func Strict() Option {
+	return func(opts *options) {
+		opts.strict = true
+	}
+}
+
+// DecimalSeparator sets the decimal separator of all floats for Marshal and Unmarshal, ex.: ',' for "6,40".
+// The 'decimal' annotation of a field takes precedence.
+func DecimalSeparator(separator byte) Option {
+	return func(opts *options) {
+		opts.decimalSeparator = separator
+	}
+}
+
+// ThousandsSeparator allows a thousands separator in all floats read by Unmarshal, ex.: '.' for "1.234,5".
+// Marshal never writes it. The 'thousands' annotation of a field takes precedence.
+func ThousandsSeparator(separator byte) Option {
+	return func(opts *options) {
+		opts.thousandsSeparator = separator
+	}
+}
+
+// MaxArrayLength limits the number of elements Unmarshal reads into an array, including top-level arrays.
+// Sizes taken from fixed or dynamic array annotations are checked before reading the elements.
+func MaxArrayLength(maxLength int) Option {
+	return func(opts *options) {
+		opts.maxArrayLength = maxLength
+	}
+}
+
+// MaxDepth limits how deep Unmarshal descends into nested structs. The top-level struct has a depth of 1.
+func MaxDepth(maxDepth int) Option {
+	return func(opts *options) {
+		opts.maxDepth = maxDepth
+	}
+}
+
+// MaxRecordBytes limits the number of bytes Unmarshal reads for a struct or a single record of a top-level array.
+// Fields are checked before they are read, so an absolute position or a length beyond the limit is never processed.
+func MaxRecordBytes(maxBytes int) Option {
+	return func(opts *options) {
+		opts.maxRecordBytes = maxBytes
+	}
+}
+
+// Returns an ErrorLimitExceeded if the record ending at 'endByte' is longer than allowed.
+func (opts *options) checkRecordBytes(endByte int) error {
+	return checkLimit(LimitRecordBytes, opts.maxRecordBytes, endByte-opts.recordStartByte)
+}
+
+// Returns an ErrorLimitExceeded if 'actual' is greater than 'max'. A 'max' of 0 or lower means unlimited.
+func checkLimit(limit string, max int, actual int) error {
+	if max > 0 && actual > max {
+		return newLimitExceededError(limit, max, actual)
+	}
+	return nil
+}
+
+// Adds 'err' to the 'collected' errors, if processing is supposed to continue after it.
+// Returns the collected errors and nil if processing can continue. Otherwise an error which has to abort
+// the processing - if there were collected errors before, all of them are returned together in an ErrorCollection.
+func (opts *options) collectError(collected []error, err error) ([]error, error) {
+
+	if err == nil {
+		return collected, nil
+	}
+
+	if errorCollection, isCollection := err.(*ErrorCollection); isCollection {
+		for _, collectedErr := range errorCollection.Errors {
+			if !opts.isCollectable(collectedErr) {
+				return nil, newErrorCollection(append(collected, errorCollection.Errors...))
+			}
+		}
+		return append(collected, errorCollection.Errors...), nil
+	}
+
+	if opts.isCollectable(err) {
+		return append(collected, err), nil
+	}
+
+	if len(collected) > 0 {
+		return nil, newErrorCollection(append(collected, err))
+	}
+
+	return collected, err
+}
+
+// Checks if processing can continue after 'err' and returns a bool accordingly.
+func (opts *options) isCollectable(err error) bool {
+
+	var errValidation *ErrorValidation
+	if opts.collectValidationErrors && errors.As(err, &errValidation) {
+		return true
+	}
+
+	if opts.lenient {
+		// without the field's bytes the rest of the record can't be read either - zero value bytes still have a known range
+		var errOutOfBounds *ErrorReadingOutOfBounds
+		var errLimitExceeded *ErrorLimitExceeded
+		return !errors.As(err, &errOutOfBounds) && !errors.As(err, &errLimitExceeded)
+	}
+
+	return false
+}
@@ -0,0 +1,45 @@
+package binfile
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// A QualifiedNumber is a number with an optional comparison qualifier in front, ex.: "<0.50", ">1000" or "~6.4",
+// as instruments report results outside of their measuring range.
+//
+// The number is read and written like a float: 'precision', 'padspace', 'sign', 'decimal' and the other annotations
+// for floats apply, but the notation is fixed unless annotated otherwise. The qualifier comes first, even before the sign.
+type QualifiedNumber struct {
+	Qualifier string // one of "<", "<=", ">", ">=", "~", "=" or "" for a plain number
+	Value     float64
+	Raw       string // the text as read, without surrounding spaces - ignored on marshaling
+}
+
+// the longest first, so "<=" is not read as "<"
+var qualifiers = []string{"<=", ">=", "<", ">", "~", "="}
+
+var qualifiedNumberType = reflect.TypeOf(QualifiedNumber{})
+
+// Returns the number with its qualifier in front, ex.: "<0.5".
+func (number QualifiedNumber) String() string {
+	return number.Qualifier + strconv.FormatFloat(number.Value, 'f', -1, 64)
+}
+
+// Returns the qualifier in front of the text of a number read from the input, and the text behind it.
+// Spaces before the qualifier are dropped, without a qualifier the text is returned as is.
+func splitQualifier(value string) (string, string) {
+	var unpadded = strings.TrimLeft(value, " ")
+	for _, qualifier := range qualifiers {
+		if strings.HasPrefix(unpadded, qualifier) {
+			return qualifier, unpadded[len(qualifier):]
+		}
+	}
+	return "", value
+}
+
+// Returns true if 'qualifier' can be written in front of a QualifiedNumber.
+func isValidQualifier(qualifier string) bool {
+	return qualifier == "" || sliceContainsString(qualifiers, qualifier)
+}
@@ -11,3 +11,8 @@ func getFieldFromStruct(structValue reflect.Value, name string) (reflect.Value,
 	}
 	return reflect.Value{}, false
 }
+
+// Returns true if 'valueType' is a struct type of this package which is read and written as a single value, ex.: QualifiedNumber.
+func isValueStructType(valueType reflect.Type) bool {
+	return valueType == qualifiedNumberType || valueType == decimalType || isNullType(valueType)
+}
@@ -0,0 +1,482 @@
+package binfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A Schema describes a record like an annotated struct does, but is defined at runtime. Records are decoded
+// into map[string]interface{} with the field names as keys - and encoded from them.
+//
+// Internally, the schema is processed as a struct type with the annotations in its 'bin' tags,
+// so all annotations work exactly the same way. The schema can be read from JSON or YAML with ParseSchema:
+//
+//	records: true
+//	fields:
+//	  - name: RecordType
+//	    type: string
+//	    bin: ":2"
+//	  - name: TestResults
+//	    type: "[]struct"
+//	    bin: "array:terminator"
+//	    fields:
+//	      - name: TestCode
+//	        type: string
+//	        bin: ":2"
+type Schema struct {
+	Records bool          `json:"records,omitempty" yaml:"records,omitempty"` // a top-level array of records separated by the terminator
+	Fields  []SchemaField `json:"fields" yaml:"fields"`
+}
+
+// A SchemaField is a field of a Schema. 'Bin' holds the same annotations as the 'bin' tag of a struct field.
+//
+// The types are "string", "int", "float32", "float64", "decimal" (Decimal), "qualified" (QualifiedNumber)
+// and "struct" - arrays of them are prefixed with "[]". Values that may be absent are pointers, ex.: "*int",
+// or Nulls, ex.: "null[int]" - not for structs. Structs and arrays of structs have 'Fields', the other types don't.
+type SchemaField struct {
+	Name   string        `json:"name" yaml:"name"`
+	Type   string        `json:"type" yaml:"type"`
+	Bin    string        `json:"bin,omitempty" yaml:"bin,omitempty"`
+	Fields []SchemaField `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// The field types of a schema and the Go types they are processed as.
+var schemaTypes = map[string]reflect.Type{
+	"string":    reflect.TypeOf(""),
+	"int":       reflect.TypeOf(0),
+	"float32":   reflect.TypeOf(float32(0)),
+	"float64":   reflect.TypeOf(float64(0)),
+	"decimal":   decimalType,
+	"qualified": qualifiedNumberType,
+}
+
+// The Nulls of the schema field types, ex.: "null[int]".
+var schemaNullTypes = map[string]reflect.Type{
+	"string":    reflect.TypeOf(Null[string]{}),
+	"int":       reflect.TypeOf(Null[int]{}),
+	"float32":   reflect.TypeOf(Null[float32]{}),
+	"float64":   reflect.TypeOf(Null[float64]{}),
+	"decimal":   reflect.TypeOf(Null[Decimal]{}),
+	"qualified": reflect.TypeOf(Null[QualifiedNumber]{}),
+}
+
+// Reads a schema from JSON or YAML. Unknown keys are rejected.
+//
+// Returns an error if the data can't be read or the schema is invalid.
+func ParseSchema(data []byte) (*Schema, error) {
+
+	var decoder = yaml.NewDecoder(bytes.NewReader(data)) // JSON is valid YAML as well
+	decoder.KnownFields(true)
+
+	var schema Schema
+	if err := decoder.Decode(&schema); err != nil {
+		return nil, err
+	}
+
+	if _, err := schema.RecordType(); err != nil {
+		return nil, err
+	}
+
+	return &schema, nil
+}
+
+// Returns the struct type a single record of the schema is processed as. Its fields have the names of the
+// schema fields along with 'bin' and 'json' tags. Returns an ErrorInvalidSchema if the schema is invalid.
+func (s *Schema) RecordType() (reflect.Type, error) {
+	if len(s.Fields) == 0 {
+		return nil, newInvalidSchemaError("", ErrorInvalidNestedFields)
+	}
+	return buildSchemaStructType(s.Fields, "")
+}
+
+// Accepts a byte array to parse with the schema.
+//
+// Returns the record as map[string]interface{} - or all records as []interface{} of them, if the schema is for records.
+// Nested structs are maps as well and arrays are []interface{}. Also returns the position the parsing ended at and
+// the same errors as Unmarshal does. In lenient mode, the result is returned along with the errors.
+func (s *Schema) Unmarshal(inputBytes []byte, enc Encoding, tz Timezone, arrayTerminator string, options ...Option) (interface{}, int, error) {
+
+	var recordType, err = s.RecordType()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var target reflect.Value
+	if s.Records {
+		target = reflect.New(reflect.SliceOf(recordType))
+	} else {
+		target = reflect.New(recordType)
+	}
+
+	position, err := Unmarshal(inputBytes, target.Interface(), enc, tz, arrayTerminator, options...)
+
+	return schemaValueOf(target.Elem()), position, err
+}
+
+// Accepts a record as map[string]interface{} - or a slice of them, if the schema is for records.
+// Nested structs are maps as well, arrays can be any slice. Missing fields are processed as zero values.
+//
+// Returns a byte array with the converted contents or an error. Values that don't fit the schema
+// are returned as ErrorInvalidSchemaValue, otherwise the same errors as Marshal returns.
+func (s *Schema) Marshal(value interface{}, padding byte, enc Encoding, tz Timezone, arrayTerminator string, options ...Option) ([]byte, error) {
+
+	var recordType, err = s.RecordType()
+	if err != nil {
+		return []byte{}, err
+	}
+
+	var targetType = recordType
+	if s.Records {
+		targetType = reflect.SliceOf(recordType)
+	}
+
+	var target = reflect.New(targetType).Elem()
+	if err = setSchemaValue(target, value, ""); err != nil {
+		return []byte{}, err
+	}
+
+	return Marshal(target.Interface(), padding, enc, tz, arrayTerminator, options...)
+}
+
+// Creates a struct type from the 'fields' of a schema. 'path' is the path of the parent field for error messages.
+func buildSchemaStructType(fields []SchemaField, path string) (reflect.Type, error) {
+
+	var structFields = make([]reflect.StructField, 0, len(fields))
+	var names = make(map[string]bool, len(fields))
+
+	for _, field := range fields {
+
+		var fieldPath = joinFieldPath(path, field.Name)
+
+		if !token.IsIdentifier(field.Name) || !token.IsExported(field.Name) {
+			return nil, newInvalidSchemaError(fieldPath, ErrorInvalidFieldName)
+		}
+		if names[field.Name] {
+			return nil, newInvalidSchemaError(fieldPath, ErrorDuplicateFieldName)
+		}
+		names[field.Name] = true
+
+		var fieldType, err = buildSchemaFieldType(field, fieldPath)
+		if err != nil {
+			return nil, err
+		}
+
+		structFields = append(structFields, reflect.StructField{
+			Name: field.Name,
+			Type: fieldType,
+			Tag:  reflect.StructTag(`bin:` + strconv.Quote(field.Bin) + ` json:` + strconv.Quote(field.Name)),
+		})
+	}
+
+	return reflect.StructOf(structFields), nil
+}
+
+// Returns the Go type of a single schema field. Its annotations are checked as well.
+func buildSchemaFieldType(field SchemaField, fieldPath string) (reflect.Type, error) {
+
+	if _, _, err := getAnnotationList(field.Bin); err != nil {
+		return nil, newInvalidSchemaError(fieldPath, err)
+	}
+
+	var typeName = strings.TrimPrefix(field.Type, "[]")
+	var isArray = typeName != field.Type
+
+	var elemType reflect.Type
+	if typeName == "struct" {
+		if len(field.Fields) == 0 {
+			return nil, newInvalidSchemaError(fieldPath, ErrorInvalidNestedFields)
+		}
+		var err error
+		if elemType, err = buildSchemaStructType(field.Fields, fieldPath); err != nil {
+			return nil, err
+		}
+	} else if primitiveType, isKnown := schemaPrimitiveType(typeName); isKnown {
+		if len(field.Fields) > 0 {
+			return nil, newInvalidSchemaError(fieldPath, ErrorInvalidNestedFields)
+		}
+		elemType = primitiveType
+	} else {
+		return nil, newInvalidSchemaError(fieldPath, ErrorUnknownFieldType)
+	}
+
+	if isArray {
+		return reflect.SliceOf(elemType), nil
+	}
+	return elemType, nil
+}
+
+// Returns the Go type of a schema field type which is not a struct or an array, ex.: "*int" or "null[decimal]",
+// along with a bool which is true if it is known. (', ok' idiom)
+func schemaPrimitiveType(typeName string) (reflect.Type, bool) {
+
+	if strings.HasPrefix(typeName, "*") {
+		var primitiveType, isKnown = schemaTypes[strings.TrimPrefix(typeName, "*")]
+		if !isKnown {
+			return nil, false
+		}
+		return reflect.PtrTo(primitiveType), true
+	}
+
+	if strings.HasPrefix(typeName, "null[") && strings.HasSuffix(typeName, "]") {
+		var nullType, isKnown = schemaNullTypes[typeName[len("null["):len(typeName)-1]]
+		return nullType, isKnown
+	}
+
+	var primitiveType, isKnown = schemaTypes[typeName]
+	return primitiveType, isKnown
+}
+
+// Converts a value of a schema's struct type into maps and slices of interface{}.
+// Nil pointers and invalid Nulls are nil.
+func schemaValueOf(value reflect.Value) interface{} {
+
+	if isNullType(value.Type()) {
+		if !value.FieldByName("Valid").Bool() {
+			return nil
+		}
+		return schemaValueOf(value.FieldByName("Value"))
+	}
+	if isValueStructType(value.Type()) {
+		return value.Interface()
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+		return schemaValueOf(value.Elem())
+
+	case reflect.Struct:
+		var result = make(map[string]interface{}, value.NumField())
+		for i := 0; i < value.NumField(); i++ {
+			result[value.Type().Field(i).Name] = schemaValueOf(value.Field(i))
+		}
+		return result
+
+	case reflect.Slice:
+		var result = make([]interface{}, value.Len())
+		for i := range result {
+			result[i] = schemaValueOf(value.Index(i))
+		}
+		return result
+	}
+
+	return value.Interface()
+}
+
+// Stores 'value' - maps, slices and numbers of any type - in 'target' of a schema's struct type. Decimals can be
+// given as numbers or text, ex.: "6.40", qualified numbers as numbers, text like "<0.5" or QualifiedNumber.
+// 'path' is the path of the field for error messages.
+func setSchemaValue(target reflect.Value, value interface{}, path string) error {
+
+	if value == nil {
+		return nil // stays a zero value - a nil pointer or an invalid Null
+	}
+
+	var source = reflect.ValueOf(value)
+
+	if isNullType(target.Type()) {
+		if err := setSchemaValue(target.FieldByName("Value"), value, path); err != nil {
+			return err
+		}
+		target.FieldByName("Valid").SetBool(true)
+		return nil
+	}
+
+	switch target.Type() {
+	case decimalType:
+
+		var number, isDecimal = schemaDecimal(source)
+		if !isDecimal {
+			return newInvalidSchemaValueError(path, value, ErrorSchemaValueMismatch)
+		}
+		target.Set(reflect.ValueOf(number))
+		return nil
+
+	case qualifiedNumberType:
+
+		var number, isQualified = schemaQualifiedNumber(source)
+		if !isQualified {
+			return newInvalidSchemaValueError(path, value, ErrorSchemaValueMismatch)
+		}
+		target.Set(reflect.ValueOf(number))
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Ptr:
+
+		var elem = reflect.New(target.Type().Elem())
+		if err := setSchemaValue(elem.Elem(), value, path); err != nil {
+			return err
+		}
+		target.Set(elem)
+
+	case reflect.Struct:
+
+		if source.Kind() != reflect.Map || source.Type().Key().Kind() != reflect.String {
+			return newInvalidSchemaValueError(path, value, ErrorSchemaValueMismatch)
+		}
+
+		var keys = make([]string, 0, source.Len())
+		for _, key := range source.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys) // report the problems in a stable order
+
+		for _, key := range keys {
+			var fieldPath = joinFieldPath(path, key)
+			var field = target.FieldByName(key)
+			if !field.IsValid() {
+				return newInvalidSchemaValueError(fieldPath, value, ErrorUnknownFieldName)
+			}
+			var fieldValue = source.MapIndex(reflect.ValueOf(key).Convert(source.Type().Key()))
+			if err := setSchemaValue(field, fieldValue.Interface(), fieldPath); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice:
+
+		if source.Kind() != reflect.Slice && source.Kind() != reflect.Array {
+			return newInvalidSchemaValueError(path, value, ErrorSchemaValueMismatch)
+		}
+
+		var slice = reflect.MakeSlice(target.Type(), source.Len(), source.Len())
+		for i := 0; i < source.Len(); i++ {
+			if err := setSchemaValue(slice.Index(i), source.Index(i).Interface(), indexedFieldName(path, i)); err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+
+	case reflect.String:
+
+		if source.Kind() != reflect.String {
+			return newInvalidSchemaValueError(path, value, ErrorSchemaValueMismatch)
+		}
+		target.SetString(source.String())
+
+	case reflect.Int:
+
+		var number, isInteger = schemaInteger(source)
+		if !isInteger || target.OverflowInt(number) {
+			return newInvalidSchemaValueError(path, value, ErrorSchemaValueMismatch)
+		}
+		target.SetInt(number)
+
+	case reflect.Float32, reflect.Float64:
+
+		var number, isNumber = schemaNumber(source)
+		if !isNumber {
+			return newInvalidSchemaValueError(path, value, ErrorSchemaValueMismatch)
+		}
+		target.SetFloat(number)
+
+	default:
+
+		return newUnsupportedTypeError(target.Type())
+	}
+
+	return nil
+}
+
+// Returns the Decimal for a number of any type or its text, ex.: "6.40", along with a bool which is true if it is one. (', ok' idiom)
+func schemaDecimal(source reflect.Value) (Decimal, bool) {
+
+	switch value := source.Interface().(type) {
+	case Decimal:
+		return value, true
+	case json.Number:
+		var number, err = ParseDecimal(value.String())
+		return number, err == nil
+	case string:
+		var number, err = ParseDecimal(value)
+		return number, err == nil
+	}
+
+	if integer, isInteger := schemaInteger(source); isInteger && source.Kind() != reflect.Float32 && source.Kind() != reflect.Float64 {
+		return NewDecimal(integer, 0), true
+	}
+	if number, isNumber := schemaNumber(source); isNumber && !math.IsInf(number, 0) && !math.IsNaN(number) {
+		var decimal, err = ParseDecimal(strconv.FormatFloat(number, 'f', -1, 64)) // the shortest text for the float
+		return decimal, err == nil
+	}
+
+	return Decimal{}, false
+}
+
+// Returns the QualifiedNumber for a number of any type or its text with a qualifier, ex.: "<0.5",
+// along with a bool which is true if it is one. (', ok' idiom)
+func schemaQualifiedNumber(source reflect.Value) (QualifiedNumber, bool) {
+
+	switch value := source.Interface().(type) {
+	case QualifiedNumber:
+		return value, true
+	case string:
+		var qualifier, text = splitQualifier(strings.TrimSpace(value))
+		var number, err = strconv.ParseFloat(text, 64)
+		return QualifiedNumber{Qualifier: qualifier, Value: number, Raw: strings.TrimSpace(value)}, err == nil
+	}
+
+	if number, isNumber := schemaNumber(source); isNumber {
+		return QualifiedNumber{Value: number}, true
+	}
+
+	return QualifiedNumber{}, false
+}
+
+// Returns the value of an integral number of any type along with a bool which is true if it is one and fits
+// into an int64 without losing digits. (', ok' idiom)
+func schemaInteger(source reflect.Value) (int64, bool) {
+
+	if number, isJSONNumber := source.Interface().(json.Number); isJSONNumber {
+		var value, isValid = new(big.Rat).SetString(number.String()) // exact, also for "12.0" or "1e3"
+		if !isValid || !value.IsInt() || !value.Num().IsInt64() {
+			return 0, false
+		}
+		return value.Num().Int64(), true
+	}
+
+	switch source.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return source.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(source.Uint()), source.Uint() <= math.MaxInt64
+	case reflect.Float32, reflect.Float64:
+		var value = source.Float()
+		return int64(value), value == math.Trunc(value) && math.Abs(value) < math.MaxInt64
+	}
+
+	return 0, false
+}
+
+// Returns the value of a number of any type along with a bool which is true if it is one. (', ok' idiom)
+func schemaNumber(source reflect.Value) (float64, bool) {
+
+	if number, isJSONNumber := source.Interface().(json.Number); isJSONNumber {
+		var value, err = number.Float64()
+		return value, err == nil
+	}
+
+	switch source.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(source.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(source.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return source.Float(), true
+	}
+
+	return 0, false
+}
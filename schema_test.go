@@ -0,0 +1,241 @@
+package binfile
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//
+//-Schema----------------------------------------------------------------------
+
+const testSchemaYAML = `
+fields:
+  - name: RecordType
+    type: string
+    bin: ":1"
+  - name: SampleId
+    type: string
+    bin: "3:6,trim"
+  - name: Count
+    type: int
+    bin: ":1"
+  - name: Flags
+    type: "[]int"
+    bin: "array:Count,:1"
+  - name: Factors
+    type: "[]float32"
+    bin: "array:2,:5,precision:2,forcesign"
+  - name: Delta
+    type: int
+    bin: ":4,padspace"
+  - name: Results
+    type: "[]struct"
+    bin: "array:terminator"
+    fields:
+      - name: TestCode
+        type: string
+        bin: ":2"
+      - name: Value
+        type: float32
+        bin: ":6,precision:2"
+`
+
+func TestSchemaUnmarshal(t *testing.T) {
+
+	schema, err := ParseSchema([]byte(testSchemaYAML))
+	assert.Nil(t, err)
+
+	var data = "Dxx  S123212+1.50-0.25-  361006.4062935.50\r"
+
+	result, position, err := schema.Unmarshal([]byte(data), EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(data), position)
+	assert.Equal(t, map[string]interface{}{
+		"RecordType": "D",
+		"SampleId":   "S123",
+		"Count":      2,
+		"Flags":      []interface{}{1, 2},
+		"Factors":    []interface{}{float32(1.5), float32(-0.25)},
+		"Delta":      -3,
+		"Results": []interface{}{
+			map[string]interface{}{"TestCode": "61", "Value": float32(6.4)},
+			map[string]interface{}{"TestCode": "62", "Value": float32(935.5)},
+		},
+	}, result)
+
+	//-------------------------------------------------------------------------
+
+	var jsonSchema = `{"records": true, "fields": [{"name": "Code", "type": "int", "bin": ":2"}]}`
+
+	schema, err = ParseSchema([]byte(jsonSchema))
+	assert.Nil(t, err)
+
+	result, _, err = schema.Unmarshal([]byte("01\r02\r"), EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"Code": 1},
+		map[string]interface{}{"Code": 2},
+	}, result)
+}
+
+func TestSchemaMarshal(t *testing.T) {
+
+	schema, err := ParseSchema([]byte(testSchemaYAML))
+	assert.Nil(t, err)
+
+	// values as decoded from JSON
+	var record map[string]interface{}
+	err = json.Unmarshal([]byte(`{
+		"RecordType": "D",
+		"SampleId": "S123",
+		"Count": 2,
+		"Flags": [1, 2],
+		"Factors": [1.5, -0.25],
+		"Delta": -3,
+		"Results": [{"TestCode": "61", "Value": 6.4}, {"TestCode": "62", "Value": 935.5}]
+	}`), &record)
+	assert.Nil(t, err)
+
+	data, err := schema.Marshal(record, ' ', EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "D    S123212+1.50-0.25-  361006.4062935.50\r", string(data))
+
+	//-------------------------------------------------------------------------
+
+	_, err = schema.Marshal(map[string]interface{}{"Count": 1.5}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+
+	var errInvalidSchemaValue *ErrorInvalidSchemaValue
+	assert.Equal(t, true, errors.As(err, &errInvalidSchemaValue))
+	assert.Equal(t, "Count", errInvalidSchemaValue.FieldPath)
+	assert.Equal(t, true, errors.Is(err, ErrorSchemaValueMismatch))
+
+	// large integers are not rounded through float64
+	var decoder = json.NewDecoder(strings.NewReader(`{"Count": 9007199254740993}`))
+	decoder.UseNumber()
+	var largeRecord map[string]interface{}
+	assert.Nil(t, decoder.Decode(&largeRecord))
+
+	recordType, err := schema.RecordType()
+	assert.Nil(t, err)
+	var largeTarget = reflect.New(recordType).Elem()
+	assert.Nil(t, setSchemaValue(largeTarget, largeRecord, ""))
+	assert.Equal(t, int64(9007199254740993), largeTarget.FieldByName("Count").Int())
+
+	assert.Nil(t, setSchemaValue(largeTarget, map[string]interface{}{"Count": int64(math.MaxInt64)}, ""))
+	assert.Equal(t, int64(math.MaxInt64), largeTarget.FieldByName("Count").Int())
+
+	assert.Nil(t, setSchemaValue(largeTarget, map[string]interface{}{"Count": json.Number("9007199254740993.0")}, ""))
+	assert.Equal(t, int64(9007199254740993), largeTarget.FieldByName("Count").Int())
+
+	for _, count := range []interface{}{json.Number("12.5"), json.Number("1e30"), uint64(math.MaxUint64), math.Inf(1)} {
+		err = setSchemaValue(largeTarget, map[string]interface{}{"Count": count}, "")
+		assert.Equal(t, true, errors.Is(err, ErrorSchemaValueMismatch), count)
+	}
+
+	_, err = schema.Marshal(map[string]interface{}{"Results": []interface{}{map[string]interface{}{"Flags": "H"}}}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Equal(t, true, errors.As(err, &errInvalidSchemaValue))
+	assert.Equal(t, "Results[0].Flags", errInvalidSchemaValue.FieldPath)
+	assert.Equal(t, true, errors.Is(err, ErrorUnknownFieldName))
+}
+
+const testSchemaValueTypesYAML = `
+fields:
+  - name: Count
+    type: "*int"
+    bin: ":2"
+  - name: Factor
+    type: null[float32]
+    bin: ":4,placeholder:****"
+  - name: Amount
+    type: decimal
+    bin: ":6"
+  - name: Result
+    type: qualified
+    bin: ":5"
+  - name: Codes
+    type: "[]null[string]"
+    bin: "array:2,:2"
+`
+
+func TestSchemaValueTypes(t *testing.T) {
+
+	schema, err := ParseSchema([]byte(testSchemaValueTypesYAML))
+	assert.Nil(t, err)
+
+	result, _, err := schema.Unmarshal([]byte("  ****006.40<00.5AB  "), EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+
+	var record = result.(map[string]interface{})
+	assert.Nil(t, record["Count"])
+	assert.Nil(t, record["Factor"])
+	assert.Equal(t, "6.40", record["Amount"].(Decimal).String())
+	assert.Equal(t, QualifiedNumber{Qualifier: "<", Value: 0.5, Raw: "<00.5"}, record["Result"])
+	assert.Equal(t, []interface{}{"AB", nil}, record["Codes"])
+
+	//-------------------------------------------------------------------------
+
+	var values map[string]interface{}
+	var decoder = json.NewDecoder(strings.NewReader(`{"Count": 7, "Factor": null, "Amount": 6.40, "Result": "<0.5", "Codes": [null, "CD"]}`))
+	decoder.UseNumber()
+	assert.Nil(t, decoder.Decode(&values))
+
+	data, err := schema.Marshal(values, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, "07****006.40<00.5  CD", string(data))
+
+	data, err = schema.Marshal(map[string]interface{}{"Amount": "-1.5", "Result": 2}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, "  ****-001.50002.\x00\x00\x00\x00", string(data))
+
+	_, err = schema.Marshal(map[string]interface{}{"Amount": "1e3"}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Equal(t, true, errors.Is(err, ErrorSchemaValueMismatch))
+
+	_, err = schema.Marshal(map[string]interface{}{"Result": "about 5"}, ' ', EncodingUTF8, TimezoneUTC, "\r")
+	assert.Equal(t, true, errors.Is(err, ErrorSchemaValueMismatch))
+
+	//-------------------------------------------------------------------------
+
+	for _, typeName := range []string{"*struct", "null[struct]", "*[]int", "null[*int]", "null[int", "**int"} {
+		_, err = ParseSchema([]byte("fields:\n  - name: A\n    type: \"" + typeName + "\"\n    bin: \":1\"\n"))
+		assert.Equal(t, true, errors.Is(err, ErrorUnknownFieldType), typeName)
+	}
+}
+
+func TestSchemaInvalid(t *testing.T) {
+
+	var errInvalidSchema *ErrorInvalidSchema
+
+	_, err := ParseSchema([]byte("fields:\n  - name: sample\n    type: string\n"))
+	assert.Equal(t, true, errors.As(err, &errInvalidSchema))
+	assert.Equal(t, "sample", errInvalidSchema.FieldPath)
+	assert.Equal(t, true, errors.Is(err, ErrorInvalidFieldName))
+
+	_, err = ParseSchema([]byte("fields:\n  - name: Block\n    type: struct\n    fields:\n      - name: Value\n        type: money\n"))
+	assert.Equal(t, true, errors.As(err, &errInvalidSchema))
+	assert.Equal(t, "Block.Value", errInvalidSchema.FieldPath)
+	assert.Equal(t, true, errors.Is(err, ErrorUnknownFieldType))
+
+	_, err = ParseSchema([]byte("fields:\n  - name: A\n    type: int\n  - name: A\n    type: int\n"))
+	assert.Equal(t, true, errors.Is(err, ErrorDuplicateFieldName))
+
+	_, err = ParseSchema([]byte("fields:\n  - name: A\n    type: struct\n"))
+	assert.Equal(t, true, errors.Is(err, ErrorInvalidNestedFields))
+
+	_, err = ParseSchema([]byte("fields:\n  - name: A\n    type: int\n    bin: \":2,trimm\"\n"))
+	assert.Equal(t, true, errors.As(err, &errInvalidSchema))
+	assert.Equal(t, "A", errInvalidSchema.FieldPath)
+	assert.Equal(t, true, errors.Is(err, ErrorUnknownAnnotation))
+
+	// misspelled keys are not ignored
+	_, err = ParseSchema([]byte("fields:\n  - name: A\n    typ: int\n"))
+	assert.NotNil(t, err)
+}
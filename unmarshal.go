@@ -1,8 +1,10 @@
 package binfile
 
 import (
+	"bytes"
 	"errors"
 	"reflect"
+	"runtime/debug"
 	"strconv"
 	"strings"
 )
@@ -16,7 +18,19 @@ import (
 // Returns an error if a problem found or nil. The parsed contents will be in the provided 'target'.
 //
 // Check the README.md for usage.
-func Unmarshal(inputBytes []byte, target interface{}, enc Encoding, tz Timezone, arrayTerminator string) (int, error) {
+func Unmarshal(inputBytes []byte, target interface{}, enc Encoding, tz Timezone, arrayTerminator string, options ...Option) (int, error) {
+	return unmarshal(inputBytes, target, enc, tz, arrayTerminator, newOptions(options))
+}
+
+// Unmarshal with the options already applied
+func unmarshal(inputBytes []byte, target interface{}, enc Encoding, tz Timezone, arrayTerminator string, opts *options) (position int, err error) {
+
+	// malformed input must never crash the caller - this is a safety net for what slipped through the checks
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			position, err = 0, newInternalPanicError(recovered, debug.Stack())
+		}
+	}()
 
 	// only pointers allowed
 	if reflect.ValueOf(target).Kind() != reflect.Ptr {
@@ -27,13 +41,36 @@ func Unmarshal(inputBytes []byte, target interface{}, enc Encoding, tz Timezone,
 	var targetKind = targetValue.Kind()
 	switch targetKind {
 	case reflect.Struct:
-		return internalUnmarshal(inputBytes, 0, targetValue, arrayTerminator, 1, enc, tz)
+		var position, err = internalUnmarshal(inputBytes, 0, targetValue, arrayTerminator, 1, enc, tz, opts)
+
+		// a collection means every field was processed, so the end of the record is known
+		if errorCollection, isCollection := err.(*ErrorCollection); opts.strict && (err == nil || isCollection) {
+			var collectedErrors []error
+			if isCollection {
+				collectedErrors = errorCollection.Errors
+			}
+			if collectedErrors, err = opts.collectError(collectedErrors, checkTrailingBytes(inputBytes, position, arrayTerminator)); err == nil && len(collectedErrors) > 0 {
+				err = newErrorCollection(collectedErrors)
+			}
+		}
+
+		return position, err
 
 	case reflect.Slice:
 		var targetInnerKind = reflect.ValueOf(targetValue).Kind()
 
 		var currentByte = 0
-		for {
+		var collectedErrors []error
+		for recordIdx := 0; ; recordIdx++ {
+			var recordStartByte = currentByte
+			opts.recordStartByte = currentByte
+
+			if err := checkLimit(LimitArrayLength, opts.maxArrayLength, recordIdx+1); err != nil {
+				if collectedErrors, err = opts.collectError(collectedErrors, withRecordPosition(err, recordIdx, currentByte)); err != nil {
+					return currentByte, err
+				}
+			}
+
 			var outputTarget = reflect.New(targetValue.Type().Elem())
 
 			switch targetInnerKind {
@@ -42,12 +79,28 @@ func Unmarshal(inputBytes []byte, target interface{}, enc Encoding, tz Timezone,
 
 			case reflect.Struct:
 
-				var processedBytes, err = internalUnmarshal(inputBytes[currentByte:], 0, outputTarget.Elem(), arrayTerminator, 1, enc, tz)
-				if err != nil {
-					return currentByte + processedBytes, err
+				opts.trace.enterField(indexedFieldName("", recordIdx))
+				var recordEndByte, err = internalUnmarshal(inputBytes, currentByte, outputTarget.Elem(), arrayTerminator, 1, enc, tz, opts)
+				opts.trace.leaveField()
+				err = withRecordPosition(err, recordIdx, 0)
+				if collectedErrors, err = opts.collectError(collectedErrors, err); err != nil {
+					if !opts.lenient {
+						return recordEndByte, err
+					}
+					// skip the broken record, the next one starts after the following terminator
+					if errorCollection, isCollection := err.(*ErrorCollection); isCollection {
+						collectedErrors = errorCollection.Errors
+					} else {
+						collectedErrors = []error{err}
+					}
+					currentByte = skipThroughTerminator(inputBytes, recordEndByte, arrayTerminator)
+					if currentByte >= len(inputBytes) {
+						return currentByte, newErrorCollection(collectedErrors)
+					}
+					continue
 				}
 
-				currentByte += processedBytes
+				currentByte = recordEndByte
 
 				targetValue = reflect.Append(targetValue, outputTarget.Elem())
 				reflect.ValueOf(target).Elem().Set(targetValue)
@@ -57,9 +110,26 @@ func Unmarshal(inputBytes []byte, target interface{}, enc Encoding, tz Timezone,
 			}
 
 			// top-level arrays are always terminator types - advance through
-			currentByte, _ = advanceThroughTerminator(inputBytes, currentByte, arrayTerminator)
+			var terminatorStartByte = currentByte
+			var hasTerminator bool
+			currentByte, hasTerminator = advanceThroughTerminator(inputBytes, currentByte, arrayTerminator)
+			opts.trace.addTerminator(terminatorStartByte, currentByte)
+
+			if opts.strict && !hasTerminator && currentByte < len(inputBytes) {
+				// the record is followed by something else - the next one starts after the following terminator
+				var nextRecordByte = skipThroughTerminator(inputBytes, currentByte, arrayTerminator)
+				var surplus = bytes.TrimSuffix(inputBytes[currentByte:nextRecordByte], []byte(arrayTerminator))
+				var err = withRecordPosition(newTrailingBytesError(currentByte, surplus), recordIdx, currentByte)
+				if collectedErrors, err = opts.collectError(collectedErrors, err); err != nil {
+					return currentByte, err
+				}
+				currentByte = nextRecordByte
+			}
 
-			if currentByte >= len(inputBytes) {
+			if currentByte >= len(inputBytes) || currentByte == recordStartByte { // an empty record without a terminator would repeat forever
+				if len(collectedErrors) > 0 {
+					return currentByte, newErrorCollection(collectedErrors)
+				}
 				return currentByte, nil // the end (do not move this lower in code, as the boundary check has to be first)
 			}
 		}
@@ -70,39 +140,74 @@ func Unmarshal(inputBytes []byte, target interface{}, enc Encoding, tz Timezone,
 
 }
 
+// Returns an ErrorTrailingBytes if anything else than a single 'arrayTerminator' follows the record ending at 'position'.
+func checkTrailingBytes(inputBytes []byte, position int, arrayTerminator string) error {
+	if position >= len(inputBytes) || string(inputBytes[position:]) == arrayTerminator {
+		return nil
+	}
+	return newTrailingBytesError(position, inputBytes[position:])
+}
+
 // use this for recursion
-func internalUnmarshal(inputBytes []byte, currentByte int, record reflect.Value, arrayTerminator string, depth int, enc Encoding, tz Timezone) (int, error) {
+func internalUnmarshal(inputBytes []byte, currentByte int, record reflect.Value, arrayTerminator string, depth int, enc Encoding, tz Timezone, opts *options) (int, error) {
 
 	var initialStartByte = currentByte
+	var collectedErrors []error
+
+	if err := checkLimit(LimitDepth, opts.maxDepth, depth); err != nil {
+		return currentByte, err
+	}
 
 	for fieldNo := 0; fieldNo < record.NumField(); fieldNo++ {
 
 		var recordField = record.Field(fieldNo)
 
-		var binTag = record.Type().Field(fieldNo).Tag.Get("bin")
+		var binTag, annotationList, hasAnnotations, err = getFieldAnnotations(record.Type(), fieldNo)
 		if !recordField.CanInterface() {
 			if binTag != "" {
-				return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, ErrorExportedFieldNotAnnotated)
+				return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, currentByte, ErrorExportedFieldNotAnnotated)
 			} else {
 				continue // TODO: this won't notify you about accidentally not exported nested structs
 			}
 		}
 
-		var annotationList, hasAnnotations = getAnnotationList(binTag)
+		if err != nil {
+			return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, currentByte, err)
+		}
+
+		if conditionField, conditionValue, hasCondition, err := getConditionAnnotation(annotationList); err != nil {
+			return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, currentByte, err)
+		} else if hasCondition {
+			isConditionMet, err := evaluateCondition(record, conditionField, conditionValue)
+			if err != nil {
+				return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, currentByte, newInvalidConditionError(conditionField, err))
+			}
+			if !isConditionMet {
+				continue // The field is not present in this record
+			}
+		}
 
 		absoluteAnnotatedPos, relativeAnnotatedLength, hasAnnotatedAddress, err := getAddressAnnotation(annotationList)
 		if err != nil {
-			return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, newInvalidAddressAnnotationError(err))
+			return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, currentByte, newInvalidAddressAnnotationError(err))
 		}
 
 		if hasAnnotatedAddress && absoluteAnnotatedPos > 0 {
 			// The current field has an absolute Address. This causes the cursor to be forwarded
 			var newPos = initialStartByte + absoluteAnnotatedPos
-			if len(inputBytes)-initialStartByte < newPos {
-				return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, newReadingOutOfBoundsError(newPos, newPos+relativeAnnotatedLength, len(inputBytes)-initialStartByte))
+			if err := opts.checkRecordBytes(newPos); err != nil {
+				if collectedErrors, err = opts.collectError(collectedErrors, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, currentByte, err)); err != nil {
+					return currentByte, err
+				}
 			}
+			if newPos > len(inputBytes) {
+				return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, currentByte, newReadingOutOfBoundsError(newPos, newPos+relativeAnnotatedLength, len(inputBytes)))
+			}
+			opts.trace.addGap(currentByte, newPos)
 			currentByte = newPos
 		}
+
+		var fieldStartByte = currentByte
 		/*
 			// Really useful debugging:
 			for k := 0; k < depth; k++ {
@@ -114,12 +219,16 @@ func internalUnmarshal(inputBytes []byte, currentByte int, record reflect.Value,
 		*/
 		var valueKind = reflect.TypeOf(recordField.Interface()).Kind()
 
-		if valueKind == reflect.Struct {
+		if valueKind == reflect.Struct && !isValueStructType(recordField.Type()) {
 
 			var err error
-			currentByte, err = internalUnmarshal(inputBytes, currentByte, recordField, arrayTerminator, depth+1, enc, tz)
+			opts.trace.enterField(record.Type().Field(fieldNo).Name)
+			currentByte, err = internalUnmarshal(inputBytes, currentByte, recordField, arrayTerminator, depth+1, enc, tz, opts)
+			opts.trace.leaveField()
 			if err != nil { // If the nested structure did fail, then bail out
-				return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, err)
+				if collectedErrors, err = opts.collectError(collectedErrors, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, fieldStartByte, err)); err != nil {
+					return currentByte, err
+				}
 			}
 
 			continue
@@ -133,12 +242,15 @@ func internalUnmarshal(inputBytes []byte, currentByte int, record reflect.Value,
 
 			var arrayAnnotation, hasArrayAnnotation = getArrayAnnotation(annotationList)
 			if !hasArrayAnnotation {
-				return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, ErrorMissingArrayAnnotation)
+				return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, fieldStartByte, ErrorMissingArrayAnnotation)
 			}
 
 			var targetKind = reflect.TypeOf(recordField.Interface()).Elem().Kind()
+			if isValueStructType(recordField.Type().Elem()) {
+				targetKind = reflect.Invalid // a single value, not a nested struct
+			}
 			if targetKind != reflect.Struct && !hasAnnotatedAddress {
-				return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, ErrorMissingAddressAnnotation)
+				return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, fieldStartByte, ErrorMissingAddressAnnotation)
 			}
 
 			var arraySize = -1
@@ -149,7 +261,12 @@ func internalUnmarshal(inputBytes []byte, currentByte int, record reflect.Value,
 				} else if fieldName, isDynamic := getArraySizeFieldName(arrayAnnotation); isDynamic {
 					arraySize, err = resolveDynamicArraySize(record, fieldName)
 					if err != nil {
-						return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, newInvalidDynamicArraySizeError(record.Type().Name(), fieldName, err))
+						return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, fieldStartByte, newInvalidDynamicArraySizeError(record.Type().Name(), fieldName, err))
+					}
+				}
+				if err := checkLimit(LimitArrayLength, opts.maxArrayLength, arraySize); err != nil {
+					if collectedErrors, err = opts.collectError(collectedErrors, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, fieldStartByte, err)); err != nil {
+						return currentByte, err
 					}
 				}
 			}
@@ -166,6 +283,10 @@ func internalUnmarshal(inputBytes []byte, currentByte int, record reflect.Value,
 					if arrayIdx == arraySize {
 						break
 					}
+				} else if err = checkLimit(LimitArrayLength, opts.maxArrayLength, arrayIdx+1); err != nil {
+					if collectedErrors, err = opts.collectError(collectedErrors, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, fieldStartByte, err)); err != nil {
+						return currentByte, err
+					}
 				}
 
 				var outputTarget = reflect.New(targetType.Elem())
@@ -174,22 +295,39 @@ func internalUnmarshal(inputBytes []byte, currentByte int, record reflect.Value,
 				switch targetKind { // Nested: all here is an array of something
 				case reflect.Struct:
 
-					currentByte, err = internalUnmarshal(inputBytes, currentByte, outputTarget.Elem(), arrayTerminator, depth+1, enc, tz)
+					opts.trace.enterField(indexedFieldName(record.Type().Field(fieldNo).Name, arrayIdx))
+					currentByte, err = internalUnmarshal(inputBytes, currentByte, outputTarget.Elem(), arrayTerminator, depth+1, enc, tz, opts)
+					opts.trace.leaveField()
 					if err != nil {
 						if !isTerminatorType && errors.Is(err, ErrorFoundZeroValueBytes) {
 							continue
 						}
-						return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, err)
+						if collectedErrors, err = opts.collectError(collectedErrors, newProcessingFieldError(indexedFieldName(record.Type().Field(fieldNo).Name, arrayIdx), binTag, lastByte, err)); err != nil {
+							return currentByte, err
+						}
 					}
 
 				default:
 
-					currentByte, err = unmarshalSimpleTypes(inputBytes, currentByte, outputTarget.Elem(), relativeAnnotatedLength, annotationList, depth+1, enc, tz)
+					if err = opts.checkRecordBytes(lastByte + relativeAnnotatedLength); err != nil {
+						if collectedErrors, err = opts.collectError(collectedErrors, newProcessingFieldError(indexedFieldName(record.Type().Field(fieldNo).Name, arrayIdx), binTag, lastByte, err)); err != nil {
+							return currentByte, err
+						}
+					}
+
+					currentByte, err = unmarshalSimpleTypes(inputBytes, currentByte, outputTarget.Elem(), relativeAnnotatedLength, annotationList, depth+1, enc, tz, opts)
+					opts.trace.addField(indexedFieldName(record.Type().Field(fieldNo).Name, arrayIdx), lastByte, lastByte+relativeAnnotatedLength, err)
 					if err != nil {
 						if !isTerminatorType && errors.Is(err, ErrorFoundZeroValueBytes) {
 							continue
 						}
-						return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, err)
+						if collectedErrors, err = opts.collectError(collectedErrors, newProcessingFieldError(indexedFieldName(record.Type().Field(fieldNo).Name, arrayIdx), binTag, lastByte, err)); err != nil {
+							return currentByte, err
+						}
+					} else if err = validateField(outputTarget.Elem(), isAbsentValue(inputBytes[lastByte:currentByte], targetType.Elem(), annotationList), annotationList); err != nil {
+						if collectedErrors, err = opts.collectError(collectedErrors, newProcessingFieldError(indexedFieldName(record.Type().Field(fieldNo).Name, arrayIdx), binTag, lastByte, err)); err != nil {
+							return currentByte, err
+						}
 					}
 				}
 
@@ -208,6 +346,7 @@ func internalUnmarshal(inputBytes []byte, currentByte int, record reflect.Value,
 				if isTerminatorType || (!isTerminatorType && arrayIdx == arraySize-1) {
 					var isFound bool
 					if currentByte, isFound = advanceThroughTerminator(inputBytes, currentByte, arrayTerminator); isFound {
+						opts.trace.addTerminator(currentByte-len(arrayTerminator), currentByte)
 						break
 					}
 				}
@@ -218,37 +357,68 @@ func internalUnmarshal(inputBytes []byte, currentByte int, record reflect.Value,
 		}
 
 		if !hasAnnotatedAddress {
-			return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, ErrorMissingAddressAnnotation)
+			return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, fieldStartByte, ErrorMissingAddressAnnotation)
+		}
+
+		if err = opts.checkRecordBytes(fieldStartByte + relativeAnnotatedLength); err != nil {
+			if collectedErrors, err = opts.collectError(collectedErrors, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, fieldStartByte, err)); err != nil {
+				return currentByte, err
+			}
 		}
 
-		currentByte, err = unmarshalSimpleTypes(inputBytes, currentByte, recordField, relativeAnnotatedLength, annotationList, depth+1, enc, tz)
+		currentByte, err = unmarshalSimpleTypes(inputBytes, currentByte, recordField, relativeAnnotatedLength, annotationList, depth+1, enc, tz, opts)
+		var errUnknownCode *ErrorUnknownCode
+		if fallbackName, hasFallback := getFallbackAnnotation(annotationList); hasFallback && errors.As(err, &errUnknownCode) {
+			if err = setFallbackCode(record, fallbackName, errUnknownCode.Code); err != nil {
+				err = newInvalidFallbackError(fallbackName, err)
+			}
+		}
+		opts.trace.addField(record.Type().Field(fieldNo).Name, fieldStartByte, fieldStartByte+relativeAnnotatedLength, err)
 		if err != nil {
 			// the last item should actually return the error but itmes before should process to advance the current byte
 			if fieldNo < record.NumField()-1 && errors.Is(err, ErrorFoundZeroValueBytes) {
 				continue
 			}
-			return currentByte, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, err)
+			if collectedErrors, err = opts.collectError(collectedErrors, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, fieldStartByte, err)); err != nil {
+				return currentByte, err
+			}
+		} else if err = validateField(recordField, isAbsentValue(inputBytes[fieldStartByte:currentByte], recordField.Type(), annotationList), annotationList); err != nil {
+			if collectedErrors, err = opts.collectError(collectedErrors, newProcessingFieldError(record.Type().Field(fieldNo).Name, binTag, fieldStartByte, err)); err != nil {
+				return currentByte, err
+			}
 		}
 	}
 
+	if len(collectedErrors) > 0 {
+		return currentByte, newErrorCollection(collectedErrors)
+	}
+
 	return currentByte, nil
 }
 
 // use this for processing end nodes
-func unmarshalSimpleTypes(inputBytes []byte, currentByte int, recordField reflect.Value, relativeAnnotatedLength int, annotationList []string, depth int, enc Encoding, tz Timezone) (int, error) {
+func unmarshalSimpleTypes(inputBytes []byte, currentByte int, recordField reflect.Value, relativeAnnotatedLength int, annotationList []string, depth int, enc Encoding, tz Timezone, opts *options) (int, error) {
 
 	if relativeAnnotatedLength > 0 {
 		// Having a length, the total length is not supposed to exceed the boundaries of the input
-		if currentByte+relativeAnnotatedLength-1 > len(inputBytes) {
+		if currentByte+relativeAnnotatedLength > len(inputBytes) {
 			return currentByte, newReadingOutOfBoundsError(currentByte, currentByte+relativeAnnotatedLength, len(inputBytes))
 		}
 	}
 
-	var byteSum = 0
-	for _, val := range inputBytes[currentByte : currentByte+relativeAnnotatedLength] {
-		byteSum += int(val)
-	}
-	if byteSum == 0 {
+	var fieldBytes = inputBytes[currentByte : currentByte+relativeAnnotatedLength]
+	var strvalue = string(fieldBytes)
+
+	var isPointer = recordField.Kind() == reflect.Ptr
+	var isNull = isNullType(recordField.Type())
+
+	var defaultLiteral, hasDefault = getDefaultAnnotation(annotationList)
+	var isDefaultApplied = hasDefault && isBlankBytes(fieldBytes)
+	var isAbsent = isAbsentValue(fieldBytes, recordField.Type(), annotationList)
+
+	if isDefaultApplied {
+		strvalue = defaultLiteral
+	} else if isZeroValueBytes(fieldBytes) && !isAbsent {
 		return currentByte + relativeAnnotatedLength, ErrorFoundZeroValueBytes
 	}
 
@@ -256,71 +426,296 @@ func unmarshalSimpleTypes(inputBytes []byte, currentByte int, recordField reflec
 		return currentByte, ErrorAnnotatedFieldNotWritable
 	}
 
+	currentByte += relativeAnnotatedLength
+
+	if isAbsent { // no value: a nil pointer, an invalid Null or the zero value
+		recordField.Set(reflect.Zero(recordField.Type()))
+		return currentByte, nil
+	}
+
+	var target = recordField
+	if isPointer {
+		target = reflect.New(recordField.Type().Elem()).Elem()
+	} else if isNull {
+		target = recordField.FieldByName("Value")
+	}
+
+	if err := setSimpleValue(target, strvalue, annotationList, opts); err != nil {
+		if isDefaultApplied {
+			return currentByte, newInvalidDefaultError(defaultLiteral, err)
+		}
+		return currentByte, err
+	}
+
+	if isPointer {
+		recordField.Set(target.Addr())
+	} else if isNull {
+		recordField.FieldByName("Valid").SetBool(true)
+	}
+
+	return currentByte, nil
+}
+
+// Returns true if all bytes of a field are zero value bytes.
+func isZeroValueBytes(fieldBytes []byte) bool {
+	for _, val := range fieldBytes {
+		if val != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns true if a field is blank: only spaces or zero value bytes.
+func isBlankBytes(fieldBytes []byte) bool {
+	return isZeroValueBytes(fieldBytes) || strings.TrimLeft(string(fieldBytes), " ") == ""
+}
+
+// Returns true if the bytes of a field of 'valueType' are read as absent value: one of the 'placeholder' literals,
+// or a blank range without a 'default' for pointers, Nulls and fields with a 'placeholder' annotation.
+func isAbsentValue(fieldBytes []byte, valueType reflect.Type, annotationList []string) bool {
+
+	var placeholders, hasPlaceholder = getPlaceholderAnnotation(annotationList)
+	if hasPlaceholder && sliceContainsString(placeholders, strings.TrimSpace(string(fieldBytes))) {
+		return true
+	}
+
+	var _, hasDefault = getDefaultAnnotation(annotationList)
+	return isBlankBytes(fieldBytes) && !hasDefault && (hasPlaceholder || valueType.Kind() == reflect.Ptr || isNullType(valueType))
+}
+
+// Converts the text read from the input into the type of 'recordField' and stores it.
+func setSimpleValue(recordField reflect.Value, strvalue string, annotationList []string, opts *options) error {
+
+	if table, hasCodeTable := getCodeTable(recordField.Type()); hasCodeTable {
+
+		value, err := table.decode(strvalue)
+		if err != nil {
+			return err
+		}
+
+		recordField.Set(reflect.ValueOf(value))
+		return nil
+	}
+
+	if recordField.Type() == decimalType {
+
+		var rawvalue = strvalue
+		var err error
+		if strvalue, err = moveSignToFront(strvalue, annotationList); err != nil {
+			return err
+		}
+		if hasAnnotationPadspace(annotationList) {
+			strvalue = strings.Replace(strvalue, " ", "", -1) // ex.: "-  3.10"
+		}
+		if strvalue, err = normalizeDecimal(strvalue, annotationList, opts); err != nil {
+			return newInvalidNumberTypeError(rawvalue, "decimal", err)
+		}
+		strvalue = insertImpliedPoint(strvalue, annotationList)
+
+		number, err := ParseDecimal(strvalue)
+		if err != nil {
+			return newInvalidNumberTypeError(rawvalue, "decimal", err)
+		}
+
+		recordField.Set(reflect.ValueOf(number))
+		return nil
+	}
+
+	if recordField.Type() == qualifiedNumberType {
+
+		var qualifier, text = splitQualifier(strvalue)
+		var number float64
+		if err := setSimpleValue(reflect.ValueOf(&number).Elem(), text, annotationList, opts); err != nil {
+			return err
+		}
+
+		recordField.Set(reflect.ValueOf(QualifiedNumber{Qualifier: qualifier, Value: number, Raw: strings.TrimSpace(strvalue)}))
+		return nil
+	}
+
 	var valueKind = reflect.TypeOf(recordField.Interface()).Kind()
 	switch valueKind {
 	case reflect.String:
 
-		strvalue := string(inputBytes[currentByte : currentByte+relativeAnnotatedLength])
-		currentByte += relativeAnnotatedLength
-
-		if hasAnnotationTrim(annotationList) {
-			strvalue = strings.TrimSpace(strvalue)
-		}
+		strvalue = trimString(strvalue, annotationList)
 
 		reflect.ValueOf(recordField.Addr().Interface()).Elem().SetString(reflect.ValueOf(strvalue).String())
 
 	case reflect.Int:
 
-		strvalue := string(inputBytes[currentByte : currentByte+relativeAnnotatedLength])
-		currentByte += relativeAnnotatedLength
-
+		var rawvalue = strvalue
+		var err error
+		if strvalue, err = moveSignToFront(strvalue, annotationList); err != nil {
+			return err
+		}
 		if hasAnnotationPadspace(annotationList) {
 			strvalue = strings.Replace(strvalue, " ", "", -1) // ex.: "-  3"
 		}
 
 		num, err := strconv.Atoi(strvalue)
 		if err != nil {
-			return currentByte, err
+			return newInvalidNumberError(rawvalue, valueKind, err)
 		}
 
 		reflect.ValueOf(recordField.Addr().Interface()).Elem().Set(reflect.ValueOf(num))
 
 	case reflect.Float32:
 
-		strvalue := string(inputBytes[currentByte : currentByte+relativeAnnotatedLength])
-		currentByte += relativeAnnotatedLength
-
+		var rawvalue = strvalue
+		var err error
+		if strvalue, err = moveSignToFront(strvalue, annotationList); err != nil {
+			return err
+		}
 		if hasAnnotationPadspace(annotationList) {
 			strvalue = strings.Replace(strvalue, " ", "", -1) // ex.: "-  3.1"
 		}
+		if strvalue, err = normalizeDecimal(strvalue, annotationList, opts); err != nil {
+			return newInvalidNumberError(rawvalue, valueKind, err)
+		}
+		strvalue = insertImpliedPoint(strvalue, annotationList)
 
 		num, err := strconv.ParseFloat(strvalue, 32)
 		if err != nil {
-			return currentByte, err
+			return newInvalidNumberError(rawvalue, valueKind, err)
 		}
 
 		reflect.ValueOf(recordField.Addr().Interface()).Elem().Set(reflect.ValueOf(float32(num)))
 
 	case reflect.Float64:
 
-		strvalue := string(inputBytes[currentByte : currentByte+relativeAnnotatedLength])
-		currentByte += relativeAnnotatedLength
-
+		var rawvalue = strvalue
+		var err error
+		if strvalue, err = moveSignToFront(strvalue, annotationList); err != nil {
+			return err
+		}
 		if hasAnnotationPadspace(annotationList) {
 			strvalue = strings.Replace(strvalue, " ", "", -1) // ex.: "-  3.1"
 		}
+		if strvalue, err = normalizeDecimal(strvalue, annotationList, opts); err != nil {
+			return newInvalidNumberError(rawvalue, valueKind, err)
+		}
+		strvalue = insertImpliedPoint(strvalue, annotationList)
 
 		num, err := strconv.ParseFloat(strvalue, 64)
 		if err != nil {
-			return currentByte, err
+			return newInvalidNumberError(rawvalue, valueKind, err)
 		}
 
 		reflect.ValueOf(recordField.Addr().Interface()).Elem().Set(reflect.ValueOf(float64(num)))
 
 	default:
 
-		return currentByte, newUnsupportedTypeError(reflect.TypeOf(recordField.Interface()))
+		return newUnsupportedTypeError(reflect.TypeOf(recordField.Interface()))
 	}
 
-	return currentByte, nil
+	return nil
+}
+
+// Removes the characters of the 'trim', 'ltrim' and 'rtrim' annotations from a string read from the input.
+// Without a character given, they remove the 'fill' character - or spaces. With 'align' or 'fill',
+// 'trim' only removes the padding on the side(s) written by Marshal, otherwise on both sides.
+func trimString(value string, annotationList []string) string {
+
+	var alignment, hasAlignment = getAlignAnnotation(annotationList)
+	var fill, hasFill = getFillAnnotation(annotationList)
+
+	if char, hasTrim := getTrimAnnotation(annotationList, "trim", fill); hasTrim {
+		if !hasAlignment && !hasFill {
+			alignment = "center" // both sides
+		}
+		value = trimAligned(value, alignment, char)
+	}
+	if char, hasTrim := getTrimAnnotation(annotationList, "ltrim", fill); hasTrim {
+		value = trimLeftByte(value, char)
+	}
+	if char, hasTrim := getTrimAnnotation(annotationList, "rtrim", fill); hasTrim {
+		value = trimRightByte(value, char)
+	}
+
+	return value
+}
+
+// Returns the text of a float read from the input with the separators of the 'decimal' and 'thousands' annotations
+// or options replaced for strconv.ParseFloat, ex.: "1.234,5" to "1234.5".
+// With another decimal separator, a '.' that is not the thousands separator is ambiguous - ex.: "1.234" with 'decimal:,' -
+// and gives an ErrorUnexpectedSeparator.
+func normalizeDecimal(value string, annotationList []string, opts *options) (string, error) {
+
+	var decimalSeparator = getDecimalSeparator(annotationList, opts)
+	if thousandsSeparator, hasThousands := getThousandsSeparator(annotationList, opts); hasThousands && thousandsSeparator != decimalSeparator {
+		value = strings.Replace(value, string([]byte{thousandsSeparator}), "", -1)
+	}
+	if decimalSeparator != '.' {
+		if strings.Contains(value, ".") {
+			return "", ErrorUnexpectedSeparator
+		}
+		value = strings.Replace(value, string([]byte{decimalSeparator}), ".", 1)
+	}
+
+	return value, nil
+}
+
+// Returns the text of a number read from the input with the point of the implied decimals inserted (see getImpliedDecimals),
+// ex.: "-00640" to "-006.40" for 'nopoint,precision:2'. Text with a point or other characters than digits is returned as is.
+func insertImpliedPoint(value string, annotationList []string) string {
+
+	var decimals, isImplied = getImpliedDecimals(annotationList)
+	if !isImplied {
+		return value
+	}
+
+	var sign, digits = "", value
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		sign, digits = digits[:1], digits[1:]
+	}
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return value // a point of its own or not a number at all
+	}
+	if len(digits) <= decimals { // a leading zero in front of the point
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+
+	return sign + digits[:len(digits)-decimals] + "." + digits[len(digits)-decimals:]
+}
+
+// Returns the text of a number read from the input with the sign of the 'sign' annotation in front, where strconv expects it,
+// ex.: "0123-" to "-0123" for 'sign:trailing'. Gives an ErrorInvalidSign if the sign is missing, in the wrong place or given twice.
+// The sign byte of 'sign:trailing' and 'sign:space' has to be one Marshal writes: '-' or for positive numbers a space,
+// with 'forcesign' a '+' instead.
+func moveSignToFront(value string, annotationList []string) (string, error) {
+
+	var signMode, _ = getSignAnnotation(annotationList)
+	if signMode == "leading" {
+		return value, nil // already in front
+	}
+
+	var positiveSign = byte(' ')
+	if hasAnnotationForceSign(annotationList) {
+		positiveSign = '+'
+	}
+
+	var sign, body = positiveSign, value
+	switch signMode {
+	case "trailing":
+		if len(value) > 0 {
+			sign, body = value[len(value)-1], value[:len(value)-1]
+		}
+	case "space":
+		if len(value) > 0 {
+			sign, body = value[0], value[1:]
+		}
+	}
+	if len(value) == 0 || (sign != positiveSign && sign != '-') {
+		return "", newInvalidSignError(value, signMode)
+	}
+
+	if unsigned := strings.TrimLeft(body, " "); strings.HasPrefix(unsigned, "-") || strings.HasPrefix(unsigned, "+") {
+		return "", newInvalidSignError(value, signMode) // a second sign
+	}
+
+	if sign == '-' {
+		return "-" + body, nil
+	}
+	return body, nil
 }
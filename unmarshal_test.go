@@ -3,6 +3,7 @@ package binfile
 import (
 	"errors"
 	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/google/uuid"
@@ -564,3 +565,1183 @@ func TestUnmarshalDynamicArray(t *testing.T) {
 	assert.Equal(t, true, errors.Is(err, ErrorUnknownFieldName))
 	assert.Equal(t, 1, position)
 }
+
+//
+//-Code Table------------------------------------------------------------------
+
+type testResultFlagUnmarshal int
+
+const (
+	testResultFlagNormalUnmarshal testResultFlagUnmarshal = iota
+	testResultFlagHighUnmarshal
+	testResultFlagLowUnmarshal
+)
+
+type testCodeTableUnmarshal struct {
+	Flag     testResultFlagUnmarshal   `bin:":2"`
+	Flags    []testResultFlagUnmarshal `bin:"array:2,:1"`
+	Other    testResultFlagUnmarshal   `bin:":2,fallback:OtherRaw"`
+	OtherRaw string
+}
+
+func TestUnmarshalCodeTable(t *testing.T) {
+
+	var err = RegisterCodeTable(map[testResultFlagUnmarshal]string{
+		testResultFlagNormalUnmarshal: "N",
+		testResultFlagHighUnmarshal:   "H",
+		testResultFlagLowUnmarshal:    "L",
+	})
+	assert.Nil(t, err)
+
+	var data = "H LNX "
+	var result testCodeTableUnmarshal
+
+	position, err := Unmarshal([]byte(data), &result, EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(data), position)
+
+	assert.Equal(t, testResultFlagHighUnmarshal, result.Flag)
+	assert.Equal(t, []testResultFlagUnmarshal{testResultFlagLowUnmarshal, testResultFlagNormalUnmarshal}, result.Flags)
+	assert.Equal(t, testResultFlagNormalUnmarshal, result.Other)
+	assert.Equal(t, "X ", result.OtherRaw)
+
+	//-------------------------------------------------------------------------
+
+	var dataUnknown = "Q LNX "
+	var resultUnknown testCodeTableUnmarshal
+
+	_, err = Unmarshal([]byte(dataUnknown), &resultUnknown, EncodingUTF8, TimezoneUTC, "\r")
+
+	var errUnknownCode *ErrorUnknownCode
+	assert.Equal(t, true, errors.As(err, &errUnknownCode))
+	assert.Equal(t, "Q ", errUnknownCode.Code)
+
+	//-------------------------------------------------------------------------
+
+	// validation rules check the literal on the wire
+	type testCodeTableValidation struct {
+		Flag testResultFlagUnmarshal `bin:":2,oneof:H|L,pattern:^[HL]$"`
+	}
+	var validated testCodeTableValidation
+
+	_, err = Unmarshal([]byte("L "), &validated, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, testResultFlagLowUnmarshal, validated.Flag)
+
+	_, err = Unmarshal([]byte("N "), &validated, EncodingUTF8, TimezoneUTC, "\r")
+	var errValidation *ErrorValidation
+	assert.Equal(t, true, errors.As(err, &errValidation))
+	assert.Equal(t, "oneof", errValidation.Rule)
+	assert.Equal(t, "N", errValidation.Value)
+
+	//-------------------------------------------------------------------------
+
+	err = RegisterCodeTable(map[testResultFlagUnmarshal]string{
+		testResultFlagHighUnmarshal: "H",
+		testResultFlagLowUnmarshal:  "H",
+	})
+	var errDuplicateCode *ErrorDuplicateCode
+	assert.Equal(t, true, errors.Is(err, errDuplicateCode))
+}
+
+//
+//-Conditional Fields----------------------------------------------------------
+
+type testConditionalUnmarshal struct {
+	SampleType string                        `bin:":1"`
+	SampleId   string                        `bin:":4"`
+	QCLevel    int                           `bin:":1,if:SampleType=Q"`
+	QCBlock    testConditionalInnerUnmarshal `bin:"if:SampleType=Q"`
+	Result     string                        `bin:":3"`
+}
+
+type testConditionalInnerUnmarshal struct {
+	Lot string `bin:":2"`
+}
+
+type testConditionalUnknownFieldUnmarshal struct {
+	SampleType string `bin:":1"`
+	QCLevel    int    `bin:":1,if:Hacaca=Q"`
+}
+
+func TestUnmarshalConditionalFields(t *testing.T) {
+
+	var dataQC = "Q00012L1123"
+	var resultQC testConditionalUnmarshal
+
+	position, err := Unmarshal([]byte(dataQC), &resultQC, EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(dataQC), position)
+
+	assert.Equal(t, "Q", resultQC.SampleType)
+	assert.Equal(t, 2, resultQC.QCLevel)
+	assert.Equal(t, "L1", resultQC.QCBlock.Lot)
+	assert.Equal(t, "123", resultQC.Result)
+
+	//-------------------------------------------------------------------------
+
+	var dataPatient = "P0001123"
+	var resultPatient testConditionalUnmarshal
+
+	position, err = Unmarshal([]byte(dataPatient), &resultPatient, EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(dataPatient), position)
+
+	assert.Equal(t, "P", resultPatient.SampleType)
+	assert.Equal(t, 0, resultPatient.QCLevel)
+	assert.Equal(t, "", resultPatient.QCBlock.Lot)
+	assert.Equal(t, "123", resultPatient.Result)
+
+	//-------------------------------------------------------------------------
+
+	var resultUnknownField testConditionalUnknownFieldUnmarshal
+
+	_, err = Unmarshal([]byte("Q1"), &resultUnknownField, EncodingUTF8, TimezoneUTC, "\r")
+
+	var errInvalidCondition *ErrorInvalidCondition
+	assert.Equal(t, true, errors.Is(err, errInvalidCondition))
+	assert.Equal(t, true, errors.Is(err, ErrorUnknownFieldName))
+}
+
+//
+//-Default Values--------------------------------------------------------------
+
+type testDefaultUnmarshal struct {
+	Blank      int     `bin:":3,default:0"`
+	ZeroBytes  int     `bin:":3,default:-1"`
+	NotBlank   int     `bin:":3,default:0"`
+	BlankFloat float32 `bin:":4,default:1.5"`
+	BlankStr   string  `bin:":2,default:NA"`
+}
+
+type testInvalidDefaultUnmarshal struct {
+	Blank int `bin:":3,default:abc"`
+}
+
+func TestUnmarshalDefaultValues(t *testing.T) {
+
+	var data = "   \x00\x00\x00012      "
+	var result testDefaultUnmarshal
+
+	position, err := Unmarshal([]byte(data), &result, EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(data), position)
+
+	assert.Equal(t, 0, result.Blank)
+	assert.Equal(t, -1, result.ZeroBytes)
+	assert.Equal(t, 12, result.NotBlank)
+	assert.Equal(t, float32(1.5), result.BlankFloat)
+	assert.Equal(t, "NA", result.BlankStr)
+
+	//-------------------------------------------------------------------------
+
+	var resultInvalid testInvalidDefaultUnmarshal
+
+	_, err = Unmarshal([]byte("   "), &resultInvalid, EncodingUTF8, TimezoneUTC, "\r")
+
+	var errInvalidDefault *ErrorInvalidDefault
+	assert.Equal(t, true, errors.Is(err, errInvalidDefault))
+}
+
+//
+//-Validation------------------------------------------------------------------
+
+type testValidationUnmarshal struct {
+	SampleId   string `bin:":6,trim,required,pattern:^S\\d+$"`
+	RackNumber int    `bin:":4,min:1,max:9999"`
+	TestCode   string `bin:":2,oneof:61|62|63"`
+	Comment    string `bin:":4,trim,max:3"`
+}
+
+func TestUnmarshalValidation(t *testing.T) {
+
+	var data = "S123  00016100  "
+	var result testValidationUnmarshal
+
+	position, err := Unmarshal([]byte(data), &result, EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(data), position)
+	assert.Equal(t, "S123", result.SampleId)
+
+	//-------------------------------------------------------------------------
+
+	var dataInvalid = "X123  000099ABCD"
+	var resultInvalid testValidationUnmarshal
+
+	_, err = Unmarshal([]byte(dataInvalid), &resultInvalid, EncodingUTF8, TimezoneUTC, "\r")
+
+	var errValidation *ErrorValidation
+	assert.Equal(t, true, errors.As(err, &errValidation))
+	assert.Equal(t, "pattern", errValidation.Rule)
+
+	//-------------------------------------------------------------------------
+
+	_, err = Unmarshal([]byte(dataInvalid), &resultInvalid, EncodingUTF8, TimezoneUTC, "\r", CollectValidationErrors())
+
+	var errCollection *ErrorCollection
+	assert.Equal(t, true, errors.As(err, &errCollection))
+	assert.Equal(t, 4, len(errCollection.Errors))
+
+	var rules []string
+	for _, collectedErr := range errCollection.Errors {
+		var errProcessingField *ErrorProcessingField
+		assert.Equal(t, true, errors.As(collectedErr, &errProcessingField))
+		assert.Equal(t, true, errors.As(collectedErr, &errValidation))
+		rules = append(rules, errValidation.Rule)
+	}
+	assert.Equal(t, []string{"pattern", "min", "oneof", "max"}, rules)
+
+	// the record is decoded nevertheless
+	assert.Equal(t, "X123", resultInvalid.SampleId)
+	assert.Equal(t, "ABCD", resultInvalid.Comment)
+}
+
+type testValidationRequiredUnmarshal struct {
+	RackNumber int     `bin:":4,required"`
+	Value      float32 `bin:":4,required"`
+	Count      int     `bin:":2,required,min:1,placeholder"`
+}
+
+func TestUnmarshalValidationRequired(t *testing.T) {
+
+	// zero is a present value
+	var data = "00000.0012"
+	var result testValidationRequiredUnmarshal
+
+	position, err := Unmarshal([]byte(data), &result, EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(data), position)
+	assert.Equal(t, 0, result.RackNumber)
+	assert.Equal(t, float32(0), result.Value)
+	assert.Equal(t, 12, result.Count)
+
+	//-------------------------------------------------------------------------
+
+	// a blank range read as absent only fails 'required', not 'min'
+	_, err = Unmarshal([]byte("00000.00  "), &result, EncodingUTF8, TimezoneUTC, "\r", CollectValidationErrors())
+
+	var errCollection *ErrorCollection
+	assert.Equal(t, true, errors.As(err, &errCollection))
+	assert.Equal(t, 1, len(errCollection.Errors))
+
+	var errValidation *ErrorValidation
+	assert.Equal(t, true, errors.As(err, &errValidation))
+	assert.Equal(t, "required", errValidation.Rule)
+	path, _ := FieldPathOf(err)
+	assert.Equal(t, "Count", path)
+}
+
+//
+//-Error Positions-------------------------------------------------------------
+
+type testErrorPositionUnmarshal struct {
+	RecordType string                            `bin:":1"`
+	Results    []testErrorPositionInnerUnmarshal `bin:"array:terminator"`
+}
+
+type testErrorPositionInnerUnmarshal struct {
+	TestCode string `bin:":2"`
+	Value    int    `bin:":3"`
+	Flags    []int  `bin:"array:2,:1"`
+}
+
+type testErrorPositionRecordUnmarshal struct {
+	RecordType string `bin:":1"`
+	Value      int    `bin:":3"`
+}
+
+func TestUnmarshalErrorPositions(t *testing.T) {
+
+	var data = "D6100112620020X"
+
+	var result testErrorPositionUnmarshal
+	_, err := Unmarshal([]byte(data), &result, EncodingUTF8, TimezoneUTC, "\r")
+
+	var errProcessingField *ErrorProcessingField
+	assert.Equal(t, true, errors.As(err, &errProcessingField))
+	assert.Equal(t, "Flags", errProcessingField.FieldName)
+	assert.Equal(t, "array:2,:1", errProcessingField.Annotations)
+
+	path, hasPath := FieldPathOf(err)
+	assert.Equal(t, true, hasPath)
+	assert.Equal(t, "Results[1].Flags[1]", path)
+
+	offset, hasOffset := OffsetOf(err)
+	assert.Equal(t, true, hasOffset)
+	assert.Equal(t, 14, offset)
+
+	_, hasRecordIndex := RecordIndexOf(err)
+	assert.Equal(t, false, hasRecordIndex)
+
+	// the wrapped errors are still reachable
+	var errNumber *strconv.NumError
+	assert.Equal(t, true, errors.As(err, &errNumber))
+
+	//-------------------------------------------------------------------------
+
+	var dataRecords = "D001\rD0X2\r"
+
+	var resultRecords []testErrorPositionRecordUnmarshal
+	_, err = Unmarshal([]byte(dataRecords), &resultRecords, EncodingUTF8, TimezoneUTC, "\r")
+
+	path, _ = FieldPathOf(err)
+	assert.Equal(t, "Value", path)
+
+	offset, _ = OffsetOf(err)
+	assert.Equal(t, 6, offset)
+
+	recordIndex, hasRecordIndex := RecordIndexOf(err)
+	assert.Equal(t, true, hasRecordIndex)
+	assert.Equal(t, 1, recordIndex)
+}
+
+//
+//-Lenient Mode----------------------------------------------------------------
+
+type testLenientUnmarshal struct {
+	RecordType string `bin:":1"`
+	UnitNo     int    `bin:":2"`
+	RackNumber int    `bin:":4"`
+	SampleId   string `bin:":4"`
+}
+
+func TestUnmarshalLenient(t *testing.T) {
+
+	// record 1 has two broken numbers, record 3 is truncated
+	var data = "D011165S001\rDXX11X5S002\rD021166S004\rD01"
+
+	var result []testLenientUnmarshal
+	position, err := Unmarshal([]byte(data), &result, EncodingUTF8, TimezoneUTC, "\r", Lenient())
+
+	assert.Equal(t, len(data), position)
+
+	assert.Equal(t, 3, len(result))
+	assert.Equal(t, testLenientUnmarshal{RecordType: "D", UnitNo: 1, RackNumber: 1165, SampleId: "S001"}, result[0])
+	assert.Equal(t, testLenientUnmarshal{RecordType: "D", UnitNo: 0, RackNumber: 0, SampleId: "S002"}, result[1])
+	assert.Equal(t, testLenientUnmarshal{RecordType: "D", UnitNo: 2, RackNumber: 1166, SampleId: "S004"}, result[2])
+
+	var errCollection *ErrorCollection
+	assert.Equal(t, true, errors.As(err, &errCollection))
+	assert.Equal(t, 3, len(errCollection.Errors))
+
+	var paths []string
+	var offsets []int
+	var recordIndices []int
+	for _, collectedErr := range errCollection.Errors {
+		path, _ := FieldPathOf(collectedErr)
+		offset, _ := OffsetOf(collectedErr)
+		recordIndex, _ := RecordIndexOf(collectedErr)
+		paths = append(paths, path)
+		offsets = append(offsets, offset)
+		recordIndices = append(recordIndices, recordIndex)
+	}
+	assert.Equal(t, []string{"UnitNo", "RackNumber", "RackNumber"}, paths)
+	assert.Equal(t, []int{13, 15, 39}, offsets)
+	assert.Equal(t, []int{1, 1, 3}, recordIndices)
+
+	var errOutOfBounds *ErrorReadingOutOfBounds
+	assert.Equal(t, true, errors.As(errCollection.Errors[2], &errOutOfBounds))
+
+	// the collected errors are found without the multi-error unwrapping of Go 1.20
+	assert.Equal(t, true, errCollection.Is(ErrorNotANumber))
+	assert.Equal(t, false, errCollection.Is(ErrorMissingArrayAnnotation))
+	errOutOfBounds = nil
+	assert.Equal(t, true, errCollection.As(&errOutOfBounds))
+	assert.NotNil(t, errOutOfBounds)
+
+	//-------------------------------------------------------------------------
+
+	var resultStrict []testLenientUnmarshal
+	_, err = Unmarshal([]byte(data), &resultStrict, EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Equal(t, false, errors.As(err, &errCollection))
+	assert.Equal(t, 1, len(resultStrict))
+
+	//-------------------------------------------------------------------------
+
+	// zero value bytes in the last field keep the rest of the record
+	var dataZeroBytes = "D011165S001\rD021166\x00\x00\x00\x00\rD031167S003"
+
+	var resultZeroBytes []testLenientUnmarshal
+	position, err = Unmarshal([]byte(dataZeroBytes), &resultZeroBytes, EncodingUTF8, TimezoneUTC, "\r", Lenient())
+
+	assert.Equal(t, len(dataZeroBytes), position)
+	assert.Equal(t, 3, len(resultZeroBytes))
+	assert.Equal(t, testLenientUnmarshal{RecordType: "D", UnitNo: 2, RackNumber: 1166, SampleId: ""}, resultZeroBytes[1])
+	assert.Equal(t, "S003", resultZeroBytes[2].SampleId)
+
+	assert.Equal(t, true, errors.As(err, &errCollection))
+	assert.Equal(t, 1, len(errCollection.Errors))
+	assert.Equal(t, true, errors.Is(err, ErrorFoundZeroValueBytes))
+	path, _ := FieldPathOf(errCollection.Errors[0])
+	assert.Equal(t, "SampleId", path)
+	recordIndex, _ := RecordIndexOf(errCollection.Errors[0])
+	assert.Equal(t, 1, recordIndex)
+}
+
+//-Invalid Numbers-------------------------------------------------------------
+
+type testInvalidNumberUnmarshal struct {
+	Count  int     `bin:":3"`
+	Ratio  float32 `bin:":6"`
+	Amount float64 `bin:":6,padspace"`
+}
+
+func TestUnmarshalInvalidNumbers(t *testing.T) {
+
+	var result testInvalidNumberUnmarshal
+	_, err := Unmarshal([]byte("1X3001.50-  3.1"), &result, EncodingUTF8, TimezoneUTC, "")
+
+	var errInvalidNumber *ErrorInvalidNumber
+	assert.Equal(t, true, errors.As(err, &errInvalidNumber))
+	assert.Equal(t, "1X3", errInvalidNumber.Value)
+	assert.Equal(t, 3, errInvalidNumber.Length)
+	assert.Equal(t, reflect.Int, errInvalidNumber.Kind)
+	assert.Equal(t, true, errors.Is(err, ErrorNotANumber))
+	assert.Equal(t, false, errors.Is(err, ErrorNumberOutOfRange))
+	assert.Equal(t, "error processing field 'Count' `:3` at byte 0: invalid int '1X3' (3 bytes): invalid syntax", err.Error())
+
+	// the cause is still reachable
+	assert.Equal(t, true, errors.Is(err, strconv.ErrSyntax))
+
+	//-------------------------------------------------------------------------
+
+	_, err = Unmarshal([]byte("12334e999-  3.1"), &result, EncodingUTF8, TimezoneUTC, "")
+
+	assert.Equal(t, true, errors.As(err, &errInvalidNumber))
+	assert.Equal(t, "34e999", errInvalidNumber.Value)
+	assert.Equal(t, reflect.Float32, errInvalidNumber.Kind)
+	assert.Equal(t, true, errors.Is(err, ErrorNumberOutOfRange))
+	assert.Equal(t, false, errors.Is(err, ErrorNotANumber))
+
+	//-------------------------------------------------------------------------
+
+	// the raw text includes the padding spaces
+	_, err = Unmarshal([]byte("123001.50-  X.1"), &result, EncodingUTF8, TimezoneUTC, "")
+
+	assert.Equal(t, true, errors.As(err, &errInvalidNumber))
+	assert.Equal(t, "-  X.1", errInvalidNumber.Value)
+	assert.Equal(t, reflect.Float64, errInvalidNumber.Kind)
+}
+
+//-Strict Mode-----------------------------------------------------------------
+
+type testStrictUnmarshal struct {
+	RecordType string `bin:":1"`
+	SampleId   string `bin:":4"`
+}
+
+func TestUnmarshalStrict(t *testing.T) {
+
+	var result testStrictUnmarshal
+
+	// only the terminator may follow the record
+	position, err := Unmarshal([]byte("DS001\r"), &result, EncodingUTF8, TimezoneUTC, "\r", Strict())
+	assert.Nil(t, err)
+	assert.Equal(t, 5, position)
+
+	_, err = Unmarshal([]byte("DS001"), &result, EncodingUTF8, TimezoneUTC, "\r", Strict())
+	assert.Nil(t, err)
+
+	position, err = Unmarshal([]byte("DS00123\r"), &result, EncodingUTF8, TimezoneUTC, "\r", Strict())
+	assert.Equal(t, 5, position)
+	assert.Equal(t, testStrictUnmarshal{RecordType: "D", SampleId: "S001"}, result)
+
+	var errTrailingBytes *ErrorTrailingBytes
+	assert.Equal(t, true, errors.As(err, &errTrailingBytes))
+	assert.Equal(t, 5, errTrailingBytes.Position)
+	assert.Equal(t, []byte("23\r"), errTrailingBytes.Surplus)
+
+	// without the option, the surplus is ignored
+	_, err = Unmarshal([]byte("DS00123\r"), &result, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+
+	//-------------------------------------------------------------------------
+
+	var data = "DS001\rDS0022\rDS003\r"
+
+	var results []testStrictUnmarshal
+	_, err = Unmarshal([]byte(data), &results, EncodingUTF8, TimezoneUTC, "\r", Strict())
+
+	assert.Equal(t, true, errors.As(err, &errTrailingBytes))
+	assert.Equal(t, 11, errTrailingBytes.Position)
+	assert.Equal(t, []byte("2"), errTrailingBytes.Surplus)
+
+	recordIndex, _ := RecordIndexOf(err)
+	assert.Equal(t, 1, recordIndex)
+	offset, _ := OffsetOf(err)
+	assert.Equal(t, 11, offset)
+
+	// in lenient mode, the following records are still read
+	var lenientResults []testStrictUnmarshal
+	position, err = Unmarshal([]byte(data), &lenientResults, EncodingUTF8, TimezoneUTC, "\r", Strict(), Lenient())
+
+	assert.Equal(t, len(data), position)
+	assert.Equal(t, 3, len(lenientResults))
+	assert.Equal(t, "S003", lenientResults[2].SampleId)
+
+	var errCollection *ErrorCollection
+	assert.Equal(t, true, errors.As(err, &errCollection))
+	assert.Equal(t, 1, len(errCollection.Errors))
+	assert.Equal(t, true, errors.As(errCollection.Errors[0], &errTrailingBytes))
+}
+
+//-Resource Limits-------------------------------------------------------------
+
+type testLimitsUnmarshal struct {
+	Count  int   `bin:":2"`
+	Values []int `bin:"array:Count,:1"`
+}
+
+type testLimitsNestedUnmarshal struct {
+	Outer struct {
+		Inner struct {
+			Value int `bin:":1"`
+		}
+	}
+}
+
+func TestUnmarshalLimits(t *testing.T) {
+
+	var result testLimitsUnmarshal
+
+	// the size is rejected before any element is read
+	_, err := Unmarshal([]byte("99123"), &result, EncodingUTF8, TimezoneUTC, "\r", MaxArrayLength(10))
+
+	var errLimitExceeded *ErrorLimitExceeded
+	assert.Equal(t, true, errors.As(err, &errLimitExceeded))
+	assert.Equal(t, LimitArrayLength, errLimitExceeded.Limit)
+	assert.Equal(t, 10, errLimitExceeded.Max)
+	assert.Equal(t, 99, errLimitExceeded.Actual)
+
+	path, _ := FieldPathOf(err)
+	assert.Equal(t, "Values", path)
+
+	_, err = Unmarshal([]byte("03123"), &result, EncodingUTF8, TimezoneUTC, "\r", MaxArrayLength(10))
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3}, result.Values)
+
+	//-------------------------------------------------------------------------
+
+	var resultTerminated testArrayUnmarshal
+	_, err = Unmarshal([]byte("1234\r0123\r"), &resultTerminated, EncodingUTF8, TimezoneUTC, "\r", MaxArrayLength(3))
+
+	assert.Equal(t, true, errors.As(err, &errLimitExceeded))
+	assert.Equal(t, 4, errLimitExceeded.Actual)
+	path, _ = FieldPathOf(err)
+	assert.Equal(t, "ALotOfInts", path)
+
+	var resultRecords []testStrictUnmarshal
+	_, err = Unmarshal([]byte("DS001\rDS002\rDS003\r"), &resultRecords, EncodingUTF8, TimezoneUTC, "\r", MaxArrayLength(2), Lenient())
+
+	assert.Equal(t, true, errors.As(err, &errLimitExceeded))
+	recordIndex, _ := RecordIndexOf(err)
+	assert.Equal(t, 2, recordIndex)
+	assert.Equal(t, 2, len(resultRecords))
+
+	//-------------------------------------------------------------------------
+
+	var resultNested testLimitsNestedUnmarshal
+	_, err = Unmarshal([]byte("1"), &resultNested, EncodingUTF8, TimezoneUTC, "\r", MaxDepth(2))
+
+	assert.Equal(t, true, errors.As(err, &errLimitExceeded))
+	assert.Equal(t, LimitDepth, errLimitExceeded.Limit)
+	assert.Equal(t, 3, errLimitExceeded.Actual)
+	path, _ = FieldPathOf(err)
+	assert.Equal(t, "Outer.Inner", path)
+
+	_, err = Unmarshal([]byte("1"), &resultNested, EncodingUTF8, TimezoneUTC, "\r", MaxDepth(3))
+	assert.Nil(t, err)
+
+	//-------------------------------------------------------------------------
+
+	var resultAddressing testValidAddressingUnmarshal
+	_, err = Unmarshal([]byte("1234xxx5678"), &resultAddressing, EncodingUTF8, TimezoneUTC, "\r", MaxRecordBytes(8))
+
+	assert.Equal(t, true, errors.As(err, &errLimitExceeded))
+	assert.Equal(t, LimitRecordBytes, errLimitExceeded.Limit)
+	assert.Equal(t, 9, errLimitExceeded.Actual)
+	path, _ = FieldPathOf(err)
+	assert.Equal(t, "Field3", path)
+
+	// the limit applies to each record of a top-level array on its own
+	_, err = Unmarshal([]byte("DS001\rDS002\r"), &resultRecords, EncodingUTF8, TimezoneUTC, "\r", MaxRecordBytes(5))
+	assert.Nil(t, err)
+}
+
+//
+//-Annotation Grammar----------------------------------------------------------
+
+type testAnnotationGrammarUnmarshal struct {
+	Code    string `bin:" :2 , trim "`
+	Comment string `bin:"0x06:5, default:'a, b'"`
+	Name    string `bin:":4,default:' \\'x\\' '"`
+	Flag    string `bin:"0X0F:1,pattern:'^[A-Z]{1,2}$'"`
+}
+
+type testUnknownAnnotationUnmarshal struct {
+	Code string `bin:":2"`
+	Name string `bin:":4,trimm"`
+}
+
+type testDuplicateAnnotationUnmarshal struct {
+	Name string `bin:":4,trim,trim"`
+}
+
+type testMisspelledArrayAnnotationUnmarshal struct {
+	Values []int `bin:"arrays:2,:1"`
+}
+
+func TestUnmarshalAnnotationGrammar(t *testing.T) {
+
+	var result testAnnotationGrammarUnmarshal
+	position, err := Unmarshal([]byte("A xxxx         Q"), &result, EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, 16, position)
+	assert.Equal(t, testAnnotationGrammarUnmarshal{Code: "A", Comment: "a, b", Name: " 'x' ", Flag: "Q"}, result)
+
+	//-------------------------------------------------------------------------
+
+	var errInvalidAnnotation *ErrorInvalidAnnotation
+
+	_, err = Unmarshal([]byte("AB1234"), &testUnknownAnnotationUnmarshal{}, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Equal(t, true, errors.As(err, &errInvalidAnnotation))
+	assert.Equal(t, "trimm", errInvalidAnnotation.Annotation)
+	assert.Equal(t, true, errors.Is(err, ErrorUnknownAnnotation))
+	assert.Equal(t, "error processing field 'Name' `:4,trimm` at byte 2: invalid annotation 'trimm': unknown annotation", err.Error())
+
+	_, err = Unmarshal([]byte("1234"), &testDuplicateAnnotationUnmarshal{}, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Equal(t, true, errors.Is(err, ErrorDuplicateAnnotation))
+	path, _ := FieldPathOf(err)
+	assert.Equal(t, "Name", path)
+
+	_, err = Unmarshal([]byte("12"), &testMisspelledArrayAnnotationUnmarshal{}, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Equal(t, true, errors.Is(err, ErrorUnknownAnnotation))
+
+	//-------------------------------------------------------------------------
+
+	for _, tag := range []string{
+		":4,default:'abc", // unclosed quote
+		":4,default:'a'b", // text after the quote
+		":4,padspace:yes", // flag with a value
+		":4,default",      // value missing
+		":4,0x:2",         // invalid position
+		":4,:2",           // two addresses
+		":4,precision:2,precision:3",
+	} {
+		var _, _, err = getAnnotationList(tag)
+		assert.Equal(t, true, errors.As(err, &errInvalidAnnotation), tag)
+	}
+
+	annotations, _, err := getAnnotationList(`:4, oneof: 'a,b' , if:Type= x ,default:'\x00\r'`)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{":4", "oneof:a,b", "if:Type= x", "default:\x00\r"}, annotations)
+
+	//-------------------------------------------------------------------------
+
+	// the annotations are cached by struct type, not by the tags
+	type testAnnotationCache struct {
+		Value int `bin:":2,trimm"`
+	}
+	for i := 0; i < 2; i++ {
+		_, err = Unmarshal([]byte("12"), &testAnnotationCache{}, EncodingUTF8, TimezoneUTC, "\r")
+		assert.Equal(t, true, errors.Is(err, ErrorUnknownAnnotation))
+	}
+	var _, isCached = structAnnotations.Load(reflect.TypeOf(testAnnotationCache{}))
+	assert.Equal(t, true, isCached)
+
+	var typeCount = 0
+	structAnnotations.Range(func(key, _ interface{}) bool {
+		_, isType := key.(reflect.Type)
+		assert.Equal(t, true, isType)
+		typeCount++
+		return true
+	})
+	assert.NotEqual(t, 0, typeCount)
+}
+
+//
+//-Alignment-------------------------------------------------------------------
+
+type testAlignmentUnmarshal struct {
+	Right     string `bin:":5,trim"`
+	Left      string `bin:":5,align:left,trim"`
+	Center    string `bin:":6,align:center,fill:_,trim"`
+	ZeroFill  string `bin:":5,fill:0,trim"`
+	Untouched string `bin:":6,align:left,fill:_"`
+}
+
+func TestUnmarshalAlignment(t *testing.T) {
+
+	var result testAlignmentUnmarshal
+	_, err := Unmarshal([]byte("   AB AB  _ABC__00A10_ A B_"), &result, EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, testAlignmentUnmarshal{
+		Right:     "AB",
+		Left:      " AB", // only the padding on the right side is removed
+		Center:    "ABC",
+		ZeroFill:  "A10",    // the trailing zero is part of the value
+		Untouched: "_ A B_", // without 'trim' nothing is removed
+	}, result)
+
+	//-------------------------------------------------------------------------
+
+	var errInvalidAnnotation *ErrorInvalidAnnotation
+	for _, tag := range []string{":4,align:middle", ":4,fill:ab", ":4,fill:''", ":4,truncate:8"} {
+		var _, _, err = getAnnotationList(tag)
+		assert.Equal(t, true, errors.As(err, &errInvalidAnnotation), tag)
+	}
+
+	annotations, _, err := getAnnotationList(`:4,fill:'\x00'`)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{":4", "fill:\x00"}, annotations)
+}
+
+//
+//-Trimming--------------------------------------------------------------------
+
+type testTrimUnmarshal struct {
+	Both      string `bin:":6,trim"`
+	Leading   string `bin:":6,ltrim"`
+	Trailing  string `bin:":6,rtrim"`
+	Tab       string `bin:":4,trim"`
+	Zeros     string `bin:":5,ltrim:0"`
+	Nul       string `bin:":5,rtrim:'\\x00'"`
+	NulFill   string `bin:":5,fill:'\\x00',align:left,rtrim"`
+	Underline string `bin:":6,trim:_"`
+}
+
+func TestUnmarshalTrim(t *testing.T) {
+
+	var data = " AB     AB    AB  \tA \r0012012\x00\x00\x00AB\x00\x00\x00_A_B__"
+	var result testTrimUnmarshal
+
+	position, err := Unmarshal([]byte(data), &result, EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(data), position)
+	assert.Equal(t, testTrimUnmarshal{
+		Both:      "AB",
+		Leading:   "AB  ",
+		Trailing:  "  AB",
+		Tab:       "\tA \r", // only spaces are removed
+		Zeros:     "120",
+		Nul:       "12",
+		NulFill:   "AB",
+		Underline: "A_B",
+	}, result)
+}
+
+//
+//-Decimal Separator-----------------------------------------------------------
+
+type testDecimalSeparatorUnmarshal struct {
+	Default   float32 `bin:":4"`
+	Comma     float32 `bin:":4,decimal:','"`
+	Thousands float64 `bin:":8,decimal:',',thousands:."`
+	Padded    float64 `bin:":6,decimal:',',padspace"`
+}
+
+func TestUnmarshalDecimalSeparator(t *testing.T) {
+
+	var data = "6.406,4001.234,5-  2,5"
+	var result testDecimalSeparatorUnmarshal
+
+	position, err := Unmarshal([]byte(data), &result, EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(data), position)
+	assert.Equal(t, testDecimalSeparatorUnmarshal{Default: 6.4, Comma: 6.4, Thousands: 1234.5, Padded: -2.5}, result)
+
+	//-------------------------------------------------------------------------
+
+	// the options apply to every field without an annotation
+	var resultOptions testDecimalSeparatorUnmarshal
+	_, err = Unmarshal([]byte("6,406,4001.234,51'23,5"), &resultOptions, EncodingUTF8, TimezoneUTC, "\r", DecimalSeparator(','), ThousandsSeparator('\''))
+
+	assert.Nil(t, err)
+	assert.Equal(t, float32(6.4), resultOptions.Default)
+	assert.Equal(t, float64(1234.5), resultOptions.Thousands) // the annotation takes precedence
+	assert.Equal(t, float64(123.5), resultOptions.Padded)
+
+	_, err = Unmarshal([]byte("6,40"), &struct {
+		Value float32 `bin:":4"`
+	}{}, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Equal(t, true, errors.Is(err, ErrorNotANumber))
+
+	//-------------------------------------------------------------------------
+
+	// with another decimal separator, a '.' is only allowed as thousands separator
+	type testAmbiguousSeparator struct {
+		Value   float64 `bin:":5,decimal:','"`
+		Amount  Decimal `bin:":5,decimal:','"`
+		Grouped float64 `bin:":5,decimal:',',thousands:'.'"`
+	}
+	var ambiguous testAmbiguousSeparator
+
+	for _, text := range []string{"1.234           ", "001,51.234 1.234"} {
+		_, err = Unmarshal([]byte(text), &ambiguous, EncodingUTF8, TimezoneUTC, "\r")
+		var errInvalidNumber *ErrorInvalidNumber
+		assert.Equal(t, true, errors.As(err, &errInvalidNumber), text)
+		assert.Equal(t, "1.234", errInvalidNumber.Value)
+		assert.Equal(t, true, errors.Is(err, ErrorUnexpectedSeparator))
+		assert.Equal(t, true, errors.Is(err, ErrorNotANumber))
+	}
+
+	_, err = Unmarshal([]byte("6.40"), &struct {
+		Value float32 `bin:":4"`
+	}{}, EncodingUTF8, TimezoneUTC, "\r", DecimalSeparator(','))
+	assert.Equal(t, true, errors.Is(err, ErrorUnexpectedSeparator))
+	assert.Equal(t, "error processing field 'Value' `:4` at byte 0: invalid float32 '6.40' (4 bytes): '.' is neither the decimal nor the thousands separator", err.Error())
+
+	_, err = Unmarshal([]byte("001,5001,51.234"), &ambiguous, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, 1234.0, ambiguous.Grouped)
+}
+
+//
+//-Sign------------------------------------------------------------------------
+
+type testSignUnmarshal struct {
+	Leading       int     `bin:":4,sign:leading"`
+	Trailing      int     `bin:":4,sign:trailing"`
+	TrailingBlank int     `bin:":4,sign:trailing"`
+	Space         int     `bin:":4,sign:space"`
+	SpacePadded   int     `bin:":4,sign:space,padspace"`
+	None          int     `bin:":4,sign:none"`
+	FloatTrailing float64 `bin:":8,sign:trailing"`
+}
+
+func TestUnmarshalSign(t *testing.T) {
+
+	var data = "-012012-012  012- 1200121.5E-03-"
+	var result testSignUnmarshal
+
+	position, err := Unmarshal([]byte(data), &result, EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(data), position)
+	assert.Equal(t, testSignUnmarshal{
+		Leading:       -12,
+		Trailing:      -12,
+		TrailingBlank: 12,
+		Space:         12,
+		SpacePadded:   -12,
+		None:          12,
+		FloatTrailing: -0.0015,
+	}, result)
+
+	//-------------------------------------------------------------------------
+
+	type testSingleSign struct {
+		Value int `bin:":4,sign:trailing"`
+	}
+	type testSingleNoSign struct {
+		Value int `bin:":4,sign:none"`
+	}
+
+	var errInvalidSign *ErrorInvalidSign
+	for _, data := range []string{"-012", "0123", "-12-", "+12 "} {
+		_, err = Unmarshal([]byte(data), &testSingleSign{}, EncodingUTF8, TimezoneUTC, "\r")
+		assert.Equal(t, true, errors.As(err, &errInvalidSign), data)
+	}
+
+	_, err = Unmarshal([]byte("-012"), &testSingleNoSign{}, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Equal(t, true, errors.As(err, &errInvalidSign))
+	assert.Equal(t, "none", errInvalidSign.Sign)
+
+	//-------------------------------------------------------------------------
+
+	// only the sign bytes Marshal writes are accepted - a '+' only with 'forcesign', which then requires it
+	type testSignBytes struct {
+		Space          int `bin:":4,sign:space"`
+		SpaceForced    int `bin:":4,sign:space,forcesign"`
+		Trailing       int `bin:":4,sign:trailing"`
+		TrailingForced int `bin:":4,sign:trailing,forcesign"`
+	}
+	var signBytes testSignBytes
+
+	_, err = Unmarshal([]byte(" 012+012012 012+"), &signBytes, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, testSignBytes{Space: 12, SpaceForced: 12, Trailing: 12, TrailingForced: 12}, signBytes)
+
+	_, err = Unmarshal([]byte("-012-012012-012-"), &signBytes, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, testSignBytes{Space: -12, SpaceForced: -12, Trailing: -12, TrailingForced: -12}, signBytes)
+
+	for _, data := range []string{"+012", " 012 012", " 012+012012+", " 012+012012 012 "} {
+		_, err = Unmarshal([]byte(data), &signBytes, EncodingUTF8, TimezoneUTC, "\r")
+		assert.Equal(t, true, errors.As(err, &errInvalidSign), data)
+	}
+}
+
+//
+//-Placeholders----------------------------------------------------------------
+
+type testPlaceholderUnmarshal struct {
+	Stars      *float32 `bin:":5,placeholder:*****|-----|NaN"`
+	Dashes     *int     `bin:":5,placeholder:*****|-----|NaN"`
+	NotANumber float64  `bin:":5,placeholder:*****|-----|NaN"`
+	Blank      *int     `bin:":3"`
+	BlankZero  int      `bin:":3,placeholder"`
+	Value      *float32 `bin:":5,placeholder:*****,required"`
+	Strings    []*int   `bin:"array:3,:2,placeholder:--"`
+}
+
+func TestUnmarshalPlaceholders(t *testing.T) {
+
+	var data = "*****-----  NaN      1.50012--\x00\x00"
+	var result testPlaceholderUnmarshal
+
+	position, err := Unmarshal([]byte(data), &result, EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(data), position)
+	assert.Nil(t, result.Stars)
+	assert.Nil(t, result.Dashes)
+	assert.Equal(t, float64(0), result.NotANumber)
+	assert.Nil(t, result.Blank)
+	assert.Equal(t, 0, result.BlankZero)
+	assert.Equal(t, float32(1.5), *result.Value)
+	assert.Equal(t, 3, len(result.Strings))
+	assert.Equal(t, 12, *result.Strings[0])
+	assert.Nil(t, result.Strings[1])
+	assert.Nil(t, result.Strings[2])
+
+	//-------------------------------------------------------------------------
+
+	// a nil pointer fails 'required'
+	_, err = Unmarshal([]byte("*****-----  NaN      *****12----"), &result, EncodingUTF8, TimezoneUTC, "\r")
+
+	var errValidation *ErrorValidation
+	assert.Equal(t, true, errors.As(err, &errValidation))
+	path, _ := FieldPathOf(err)
+	assert.Equal(t, "Value", path)
+
+	// other text is still an error
+	_, err = Unmarshal([]byte("**-**"), &result, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Equal(t, true, errors.Is(err, ErrorNotANumber))
+}
+
+//
+//-Qualified Numbers-----------------------------------------------------------
+
+type testQualifiedNumberUnmarshal struct {
+	Below    QualifiedNumber   `bin:":6"`
+	Above    QualifiedNumber   `bin:":6"`
+	Plain    QualifiedNumber   `bin:":6"`
+	Padded   QualifiedNumber   `bin:":6,padspace"`
+	Comma    QualifiedNumber   `bin:":6,decimal:','"`
+	Negative QualifiedNumber   `bin:":6,sign:trailing"`
+	Results  []QualifiedNumber `bin:"array:2,:3"`
+}
+
+func TestUnmarshalQualifiedNumbers(t *testing.T) {
+
+	var data = "  <0.5>=1000006.40<  1.5~12,50<0012-<=5~1."
+	var result testQualifiedNumberUnmarshal
+
+	position, err := Unmarshal([]byte(data), &result, EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(data), position)
+	assert.Equal(t, QualifiedNumber{Qualifier: "<", Value: 0.5, Raw: "<0.5"}, result.Below)
+	assert.Equal(t, QualifiedNumber{Qualifier: ">=", Value: 1000, Raw: ">=1000"}, result.Above)
+	assert.Equal(t, QualifiedNumber{Qualifier: "", Value: 6.4, Raw: "006.40"}, result.Plain)
+	assert.Equal(t, QualifiedNumber{Qualifier: "<", Value: 1.5, Raw: "<  1.5"}, result.Padded)
+	assert.Equal(t, QualifiedNumber{Qualifier: "~", Value: 12.5, Raw: "~12,50"}, result.Comma)
+	assert.Equal(t, QualifiedNumber{Qualifier: "<", Value: -12, Raw: "<0012-"}, result.Negative)
+	assert.Equal(t, []QualifiedNumber{
+		{Qualifier: "<=", Value: 5, Raw: "<=5"},
+		{Qualifier: "~", Value: 1, Raw: "~1."},
+	}, result.Results)
+
+	//-------------------------------------------------------------------------
+
+	type testQualifiedNumberValidation struct {
+		Value QualifiedNumber `bin:":5,max:100"`
+	}
+	var validated testQualifiedNumberValidation
+
+	_, err = Unmarshal([]byte(">1000"), &validated, EncodingUTF8, TimezoneUTC, "\r")
+	var errValidation *ErrorValidation
+	assert.Equal(t, true, errors.As(err, &errValidation))
+	assert.Equal(t, ">1000", errValidation.Value)
+
+	_, err = Unmarshal([]byte("*1000"), &validated, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Equal(t, true, errors.Is(err, ErrorNotANumber))
+}
+
+//
+//-Decimals--------------------------------------------------------------------
+
+type testDecimalUnmarshal struct {
+	Scaled   Decimal   `bin:":6"`
+	Integral Decimal   `bin:":4"`
+	Padded   Decimal   `bin:":6,padspace"`
+	Negative Decimal   `bin:":6,sign:trailing"`
+	Comma    Decimal   `bin:":8,decimal:',',thousands:'.'"`
+	Exact    Decimal   `bin:":22"`
+	Values   []Decimal `bin:"array:2,:3"`
+}
+
+func TestUnmarshalDecimals(t *testing.T) {
+
+	var data = "006.400012-  3.101.50-1.234,50+0.10000000000000000001.5.25"
+	var result testDecimalUnmarshal
+
+	position, err := Unmarshal([]byte(data), &result, EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(data), position)
+	assert.Equal(t, "6.40", result.Scaled.String())
+	assert.Equal(t, 2, result.Scaled.Scale())
+	assert.Equal(t, "12", result.Integral.String())
+	assert.Equal(t, "-3.1", result.Padded.String())
+	assert.Equal(t, "-1.50", result.Negative.String())
+	assert.Equal(t, "1234.50", result.Comma.String())
+	assert.Equal(t, "0.1000000000000000000", result.Exact.String())
+	assert.Equal(t, 0, result.Exact.Cmp(NewDecimal(1, 1)))
+	assert.Equal(t, "1.5", result.Values[0].String())
+	assert.Equal(t, ".25", data[len(data)-3:])
+	assert.Equal(t, "0.25", result.Values[1].String())
+	assert.Equal(t, 0.25, result.Values[1].Float64())
+
+	//-------------------------------------------------------------------------
+
+	type testDecimalValidation struct {
+		Value Decimal `bin:":4,required,max:10"`
+	}
+	var validated testDecimalValidation
+
+	_, err = Unmarshal([]byte("10.5"), &validated, EncodingUTF8, TimezoneUTC, "\r")
+	var errValidation *ErrorValidation
+	assert.Equal(t, true, errors.As(err, &errValidation))
+	assert.Equal(t, "max", errValidation.Rule)
+
+	// zero is a present value
+	_, err = Unmarshal([]byte("0.00"), &validated, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Nil(t, err)
+	assert.Equal(t, "0.00", validated.Value.String())
+
+	_, err = Unmarshal([]byte("1e10"), &validated, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Equal(t, true, errors.Is(err, ErrorNotANumber))
+	assert.Equal(t, "error processing field 'Value' `:4,required,max:10` at byte 0: invalid decimal '1e10' (4 bytes): invalid syntax", err.Error())
+
+	//-------------------------------------------------------------------------
+
+	for _, text := range []string{"", ".", "-", "1.2.3", "1,5", " 1"} {
+		_, err = ParseDecimal(text)
+		assert.Equal(t, true, errors.Is(err, strconv.ErrSyntax), text)
+	}
+	number, err := ParseDecimal("+.050")
+	assert.Nil(t, err)
+	assert.Equal(t, "0.050", number.String())
+	assert.Equal(t, int64(50), number.Unscaled().Int64())
+	assert.Equal(t, "640", NewDecimal(64, -1).String())
+	assert.Equal(t, "0", Decimal{}.String())
+	assert.Equal(t, -1, NewDecimal(-5, 3).Cmp(Decimal{}))
+}
+
+//
+//-Implied decimals------------------------------------------------------------
+
+type testImpliedDecimalUnmarshal struct {
+	Decimal  Decimal `bin:":5,nopoint,precision:2"`
+	Short    Decimal `bin:":2,nopoint,precision:3"`
+	Float    float32 `bin:":5,nopoint,precision:2"`
+	Negative float64 `bin:":5,nopoint,precision:2,sign:trailing"`
+	Padded   float64 `bin:":5,nopoint,precision:2,padspace"`
+	Explicit Decimal `bin:":5,nopoint,precision:2"`
+}
+
+func TestUnmarshalImpliedDecimals(t *testing.T) {
+
+	var data = "0064005006400150-  12506.40"
+	var result testImpliedDecimalUnmarshal
+
+	position, err := Unmarshal([]byte(data), &result, EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(data), position)
+	assert.Equal(t, "6.40", result.Decimal.String())
+	assert.Equal(t, 2, result.Decimal.Scale())
+	assert.Equal(t, "0.005", result.Short.String())
+	assert.Equal(t, float32(6.4), result.Float)
+	assert.Equal(t, -1.5, result.Negative)
+	assert.Equal(t, 1.25, result.Padded)
+	// a point in the input is read as it is
+	assert.Equal(t, "6.40", result.Explicit.String())
+
+	//-------------------------------------------------------------------------
+
+	_, err = Unmarshal([]byte("0064x05006400150-  12506.40"), &result, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Equal(t, true, errors.Is(err, ErrorNotANumber))
+}
+
+//
+//-Nulls-----------------------------------------------------------------------
+
+type testNullUnmarshal struct {
+	Number   Null[int]             `bin:":3"`
+	Zeros    Null[int]             `bin:":3"`
+	Text     Null[string]          `bin:":4,trim"`
+	Blank    Null[string]          `bin:":4"`
+	Float    Null[float64]         `bin:":5,placeholder:*****"`
+	Missing  Null[float32]         `bin:":5,placeholder:*****"`
+	Decimal  Null[Decimal]         `bin:":5"`
+	Result   Null[QualifiedNumber] `bin:":5"`
+	Default  Null[int]             `bin:":2,default:7"`
+	Values   []Null[int]           `bin:"array:3,:2"`
+	Required Null[int]             `bin:":2"`
+}
+
+func TestUnmarshalNulls(t *testing.T) {
+
+	var data = "012\x00\x00\x00 ab     1.5e2*****06.40<0.50  01  \x00\x0099"
+	var result testNullUnmarshal
+
+	position, err := Unmarshal([]byte(data), &result, EncodingUTF8, TimezoneUTC, "\r")
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(data), position)
+	assert.Equal(t, Null[int]{Value: 12, Valid: true}, result.Number)
+	assert.Equal(t, Null[int]{}, result.Zeros)
+	assert.Equal(t, NewNull("ab"), result.Text)
+	assert.Equal(t, Null[string]{}, result.Blank)
+	assert.Equal(t, NewNull(150.0), result.Float)
+	assert.Equal(t, Null[float32]{}, result.Missing)
+	assert.Equal(t, true, result.Decimal.Valid)
+	assert.Equal(t, "6.40", result.Decimal.Value.String())
+	assert.Equal(t, NewNull(QualifiedNumber{Qualifier: "<", Value: 0.5, Raw: "<0.50"}), result.Result)
+	assert.Equal(t, NewNull(7), result.Default)
+	assert.Equal(t, []Null[int]{NewNull(1), {}, {}}, result.Values)
+	assert.Equal(t, NewNull(99), result.Required)
+
+	//-------------------------------------------------------------------------
+
+	type testNullValidation struct {
+		Value Null[int] `bin:":2,required,max:10"`
+	}
+	var validated testNullValidation
+
+	_, err = Unmarshal([]byte("  "), &validated, EncodingUTF8, TimezoneUTC, "\r")
+	var errValidation *ErrorValidation
+	assert.Equal(t, true, errors.As(err, &errValidation))
+	assert.Equal(t, "required", errValidation.Rule)
+
+	_, err = Unmarshal([]byte("11"), &validated, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Equal(t, true, errors.As(err, &errValidation))
+	assert.Equal(t, "max", errValidation.Rule)
+
+	_, err = Unmarshal([]byte("1x"), &validated, EncodingUTF8, TimezoneUTC, "\r")
+	assert.Equal(t, true, errors.Is(err, ErrorNotANumber))
+}
@@ -0,0 +1,137 @@
+package binfile
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var validationPatterns sync.Map // compiled 'pattern' annotations by their expression
+
+// Checks the value in 'recordField' against the validation annotations 'required', 'min', 'max', 'pattern' and 'oneof'.
+// Returns an ErrorValidation for the first violated rule or nil.
+//
+// Numbers are compared by their value in 'min' and 'max', strings by their length in characters.
+// Every other rule uses the text representation of the value, strings without the surrounding spaces
+// and types with a code table by their wire literal.
+// Pointers and Nulls are checked by the value they hold. Nil pointers, invalid Nulls and values read as absent
+// ('isAbsent', see isAbsentValue) only fail 'required' - as do blank strings, while a zero number is a present value.
+func validateField(recordField reflect.Value, isAbsent bool, annotationList []string) error {
+
+	if isNullType(recordField.Type()) {
+		if !recordField.FieldByName("Valid").Bool() {
+			recordField = reflect.Zero(reflect.PtrTo(recordField.Type())) // checked like a nil pointer
+		} else {
+			recordField = recordField.FieldByName("Value")
+		}
+	}
+
+	if recordField.Kind() == reflect.Ptr {
+		isAbsent = isAbsent || recordField.IsNil()
+		if !isAbsent {
+			recordField = recordField.Elem()
+		}
+	}
+
+	if isAbsent { // no value - only 'required' can be violated
+		if sliceContainsString(annotationList, "required") {
+			return newValidationError("required", "", "")
+		}
+		return nil
+	}
+
+	var text = fmt.Sprint(recordField.Interface())
+	if table, hasCodeTable := getCodeTable(recordField.Type()); hasCodeTable {
+		if literal, err := table.encode(recordField.Interface()); err == nil {
+			text = strings.TrimSpace(literal) // as on the wire
+		}
+	} else if recordField.Kind() == reflect.String {
+		text = strings.TrimSpace(recordField.String())
+	}
+
+	for _, val := range annotationList {
+
+		var rule, parameter = val, ""
+		if parts := strings.SplitN(val, ":", 2); len(parts) == 2 {
+			rule, parameter = parts[0], parts[1]
+		}
+
+		switch rule {
+		case "required":
+
+			if text == "" {
+				return newValidationError(rule, parameter, text)
+			}
+
+		case "min", "max":
+
+			var limit, err = strconv.ParseFloat(parameter, 64)
+			if err != nil {
+				return newInvalidValidationRuleError(val, err)
+			}
+
+			var actual float64
+			switch recordField.Kind() {
+			case reflect.Int:
+				actual = float64(recordField.Int())
+			case reflect.Float32, reflect.Float64:
+				actual = recordField.Float()
+			case reflect.Struct:
+				switch number := recordField.Interface().(type) {
+				case QualifiedNumber:
+					actual = number.Value
+				case Decimal:
+					actual = number.Float64()
+				default:
+					return newUnsupportedTypeError(recordField.Type())
+				}
+			case reflect.String:
+				actual = float64(utf8.RuneCountInString(text))
+			default:
+				return newUnsupportedTypeError(recordField.Type())
+			}
+
+			if (rule == "min" && actual < limit) || (rule == "max" && actual > limit) {
+				return newValidationError(rule, parameter, text)
+			}
+
+		case "pattern":
+
+			var expr, err = getValidationPattern(parameter)
+			if err != nil {
+				return newInvalidValidationRuleError(val, err)
+			}
+			if !expr.MatchString(text) {
+				return newValidationError(rule, parameter, text)
+			}
+
+		case "oneof":
+
+			if !sliceContainsString(strings.Split(parameter, "|"), text) {
+				return newValidationError(rule, parameter, text)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns the compiled regular expression for a 'pattern' annotation. Compiled expressions are cached.
+func getValidationPattern(pattern string) (*regexp.Regexp, error) {
+
+	if expr, isCached := validationPatterns.Load(pattern); isCached {
+		return expr.(*regexp.Regexp), nil
+	}
+
+	var expr, err = regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	validationPatterns.Store(pattern, expr)
+
+	return expr, nil
+}